@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/mahcks/gowizard/pkg/generator"
+	"github.com/mahcks/gowizard/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// addCmd represents the add command
+var addCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add an adapter or service to a project generated by gowizard.",
+	Long: `Add an adapter or service to a project that was already generated by gowizard.
+
+Only internal/app/app.go, config/config.go, the config YAML files and the new pkg folder are changed.
+gowizard add adapter redis --path /path/to/module
+gowizard add service rest=gin --path /path/to/module`,
+}
+
+// addAdapterCmd represents the add adapter command
+var addAdapterCmd = &cobra.Command{
+	Use:   "adapter [adapter...]",
+	Short: "Add adapters to an existing project, i.e. mariadb, redis",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		gen, err := loadProject(cmd)
+		if err != nil {
+			utils.PrintError("%s", err)
+			return
+		}
+
+		for _, adapter := range args {
+			err = gen.AddAdapter(cmd.Context(), strings.ToLower(adapter))
+			if err != nil {
+				utils.PrintError("error adding adapter %s: %s", adapter, err)
+				rollback(gen)
+				return
+			}
+		}
+	},
+}
+
+// addServiceCmd represents the add service command
+var addServiceCmd = &cobra.Command{
+	Use:   "service [service=flavor...]",
+	Short: "Add services to an existing project, i.e. rest=gin",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		services, err := parseServices(args)
+		if err != nil {
+			utils.PrintError("%s", err)
+			return
+		}
+
+		gen, err := loadProject(cmd)
+		if err != nil {
+			utils.PrintError("%s", err)
+			return
+		}
+
+		// Services are added in the order of the arguments, so the packages they're allocated are the same every run
+		for _, arg := range args {
+			service, _, _ := strings.Cut(strings.ToLower(arg), "=")
+			flavor, ok := services[service]
+			if !ok {
+				continue
+			}
+			delete(services, service)
+
+			err = gen.AddService(cmd.Context(), service, flavor)
+			if err != nil {
+				utils.PrintError("error adding service %s: %s", service, err)
+				rollback(gen)
+				return
+			}
+		}
+	},
+}
+
+// loadProject creates a generator for the project at the path flag
+func loadProject(cmd *cobra.Command) (*generator.Generator, error) {
+	path, err := cmd.Flags().GetString("path")
+	if err != nil {
+		return nil, err
+	}
+
+	gen := newGenerator()
+	err = gen.LoadProject(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return gen, nil
+}
+
+func init() {
+	rootCmd.AddCommand(addCmd)
+	addCmd.AddCommand(addAdapterCmd)
+	addCmd.AddCommand(addServiceCmd)
+
+	addCmd.PersistentFlags().StringP("path", "p", "./", "Path to the generated module")
+}
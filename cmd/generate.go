@@ -1,7 +1,14 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mahcks/gowizard/pkg/dryrun"
 	"github.com/mahcks/gowizard/pkg/generator"
+	"github.com/mahcks/gowizard/pkg/spec"
 	"github.com/mahcks/gowizard/pkg/utils"
 	"github.com/spf13/cobra"
 )
@@ -10,17 +17,36 @@ import (
 var generateCmd = &cobra.Command{
 	Use:   "generate",
 	Short: "Generate a new project.",
-	Long:  `Generate a new Go module with a given name and path. You can also specifiy services and adapters to be included in the project.`,
+	Long: `Generate a new Go module with a given name and path. You can also specifiy services and adapters to be included in the project.
+
+The whole project can also be described in a YAML or JSON spec file:
+gowizard generate --from spec.yaml`,
 	Run: func(cmd *cobra.Command, args []string) {
+		s := &spec.Spec{}
+
+		// Load the spec file if one is given, flags that are explicitly set will override its values
+		specPath, err := cmd.Flags().GetString("from")
+		if err != nil {
+			utils.PrintError("error getting from flag: %s", err)
+			return
+		}
+
+		if specPath != "" {
+			s, err = spec.Load(specPath)
+			if err != nil {
+				utils.PrintError("error loading spec: %s", err)
+				return
+			}
+		}
+
 		moduleName, err := cmd.Flags().GetString("module")
 		if err != nil {
 			utils.PrintError("error getting module flag: %s", err)
 			return
 		}
 
-		if moduleName == "" {
-			utils.PrintError("module name is required")
-			return
+		if cmd.Flags().Changed("module") || s.Module == "" {
+			s.Module = moduleName
 		}
 
 		path, err := cmd.Flags().GetString("path")
@@ -29,35 +55,56 @@ var generateCmd = &cobra.Command{
 			return
 		}
 
-		if path == "" {
-			utils.PrintError("module path is required")
-			return
+		if cmd.Flags().Changed("path") || s.Path == "" {
+			s.Path = path
 		}
 
-		// Open the directory user has given
-		isEmpty, err := utils.IsDirEmpty(path)
+		// Fetch adapters from flags
+		adapters, err := cmd.Flags().GetStringSlice("adapter")
 		if err != nil {
-			utils.PrintError("unable to open file: %s" + err.Error())
+			utils.PrintError("error getting adapter flags: %s", err)
 			return
 		}
 
-		if !isEmpty {
-			utils.PrintError("The directory you have specified is not empty.")
+		if cmd.Flags().Changed("adapter") {
+			s.Adapters = adapters
+		}
+
+		// Services are given as service=flavor, i.e. rest=gin
+		services, err := cmd.Flags().GetStringSlice("service")
+		if err != nil {
+			utils.PrintError("error getting service flags: %s", err)
 			return
 		}
 
-		// Fetch adapters from flags
-		adapters, err := cmd.Flags().GetStringSlice("adapter")
+		if cmd.Flags().Changed("service") {
+			s.Services, err = parseServices(services)
+			if err != nil {
+				utils.PrintError("%s", err)
+				return
+			}
+		}
+
+		// Without a logger the project prints with fmt, slog would need Go 1.21
+		logger, err := cmd.Flags().GetString("logger")
 		if err != nil {
-			utils.PrintError("error getting adapter flags: %s", err)
+			utils.PrintError("error getting logger flag: %s", err)
 			return
 		}
 
-		/* services, err := cmd.Flags().GetStringSlice("service")
+		if cmd.Flags().Changed("logger") {
+			s.Logger = logger
+		}
+
+		migrations, err := cmd.Flags().GetString("migrations")
 		if err != nil {
-			utils.PrintError("error getting service flags: %s", err)
+			utils.PrintError("error getting migrations flag: %s", err)
 			return
-		} */
+		}
+
+		if cmd.Flags().Changed("migrations") {
+			s.Migrations = migrations
+		}
 
 		// Get the version of Go to use, defaults to the users latest installed version
 		goVersion, err := cmd.Flags().GetString("go-version")
@@ -66,6 +113,10 @@ var generateCmd = &cobra.Command{
 			return
 		}
 
+		if cmd.Flags().Changed("go-version") || s.GoVersion == "" {
+			s.GoVersion = goVersion
+		}
+
 		// Get the template to use
 		template, err := cmd.Flags().GetString("template")
 		if err != nil {
@@ -73,33 +124,140 @@ var generateCmd = &cobra.Command{
 			return
 		}
 
-		gen := generator.NewGenerator()
-		gen.SetSettings(moduleName, goVersion, path, adapters, nil)
+		if cmd.Flags().Changed("template") {
+			s.Template = template
+		}
+
+		err = s.Validate()
+		if err != nil {
+			utils.PrintError("%s", err)
+			return
+		}
+
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			utils.PrintError("error getting dry-run flag: %s", err)
+			return
+		}
+
+		verifyProject, err := cmd.Flags().GetBool("verify")
+		if err != nil {
+			utils.PrintError("error getting verify flag: %s", err)
+			return
+		}
+
+		// Open the directory user has given
+		isEmpty, err := utils.IsDirEmpty(s.Path)
+		if err != nil {
+			utils.PrintError("unable to open file: %s", err)
+			return
+		}
+
+		if !isEmpty {
+			utils.PrintError("The directory you have specified is not empty.")
+			return
+		}
+
+		// A dry run writes the project to memory and prints it instead
+		var dryRunFs *dryrun.Fs
+		if dryRun {
+			s.Path, err = filepath.Abs(s.Path)
+			if err != nil {
+				utils.PrintError("error getting absolute path: %s", err)
+				return
+			}
+
+			dryRunFs = dryrun.NewFs()
+		}
+
+		gen := newGenerator(
+			generator.WithModule(s.Module),
+			generator.WithGoVersion(s.GoVersion),
+			generator.WithPath(s.Path),
+			generator.WithAdapters(s.Adapters...),
+			generator.WithServices(s.Services),
+			generator.WithLogger(noneToEmpty(s.Logger)),
+			generator.WithMigrations(noneToEmpty(s.Migrations)),
+		)
+		if dryRun {
+			gen.Configure(generator.WithFs(dryRunFs), generator.WithDryRun(true))
+		}
 
 		// If a template is specified, use it
-		if template != "" {
-			err = gen.UseTemplate(template, false)
+		if s.Template != "" {
+			err = gen.UseTemplate(cmd.Context(), s.Template, s.Options.CustomTemplate)
 			if err != nil {
 				utils.PrintError("error setting template: %s", err)
+				rollback(gen)
 				return
 			}
+		} else {
+			// Enable the adapters the chosen ones depend on, each one is reported as a step
+			gen.ResolveRequirements()
 
-			return
+			err = gen.Generate(cmd.Context())
+			if err != nil {
+				utils.PrintError("%s", err)
+
+				rollback(gen)
+				return
+			}
 		}
 
-		err = gen.Generate()
-		if err != nil {
-			utils.PrintError("%s", err)
+		if dryRun {
+			err = dryRunFs.Print(os.Stdout, s.Path)
+			if err != nil {
+				utils.PrintError("error printing dry run: %s", err)
+				return
+			}
+		}
 
-			errRollback := gen.Rollback()
-			if errRollback != nil {
-				utils.PrintError("error rolling back: %s", errRollback)
+		if verifyProject {
+			compileErrs, err := gen.Verify()
+			if err != nil {
+				utils.PrintError("error verifying project: %s", err)
+				return
 			}
-			return
+
+			for _, compileErr := range compileErrs {
+				utils.PrintError("%s", compileErr)
+			}
+
+			if len(compileErrs) != 0 {
+				utils.PrintError("%d compile error(s) in the generated project", len(compileErrs))
+				return
+			}
+
+			fmt.Println("Verified, the generated project type-checks")
 		}
 	},
 }
 
+// parseServices parses service flags in the form of service=flavor into a map of service to flavor
+func parseServices(values []string) (map[string]string, error) {
+	services := make(map[string]string, len(values))
+	for _, value := range values {
+		service, flavor, found := strings.Cut(value, "=")
+		if !found || service == "" || flavor == "" {
+			return nil, fmt.Errorf("invalid service %q, expected the format service=flavor, i.e. rest=gin", value)
+		}
+
+		services[strings.ToLower(service)] = strings.ToLower(flavor)
+	}
+
+	return services, nil
+}
+
+// noneToEmpty returns the name the generator gets for the logger or migrations of a spec, none is no logger or migrations at all
+func noneToEmpty(name string) string {
+	name = strings.ToLower(name)
+	if name == "none" {
+		return ""
+	}
+
+	return name
+}
+
 func init() {
 	rootCmd.AddCommand(generateCmd)
 
@@ -114,7 +272,12 @@ func init() {
 	generateCmd.Flags().StringP("path", "p", "./", "Path to the module")
 	generateCmd.Flags().StringP("go-version", "v", cmdVersion, "Go version to use - defaults to your latest installed version")
 	generateCmd.Flags().StringP("template", "t", "", "Template to use for the project")
+	generateCmd.Flags().StringP("from", "f", "", "Path to a YAML or JSON spec file describing the project")
+	generateCmd.Flags().Bool("dry-run", false, "Print the files that would be generated without writing anything to disk, a template is not cloned")
+	generateCmd.Flags().Bool("verify", false, "Type-check the generated project offline and report compile errors")
 
 	generateCmd.Flags().StringSliceP("adapter", "a", []string{}, "Add an adapter to the project, i.e. mariadb, redis")
-	generateCmd.Flags().StringSliceP("service", "s", []string{}, "Add a service to the project, i.e. REST HTTP server for Gin, or GQL server")
+	generateCmd.Flags().StringSliceP("service", "s", []string{}, "Add a service to the project as service=flavor, i.e. rest=gin or gql=gqlgen")
+	generateCmd.Flags().StringP("logger", "l", "none", "Logger of the project: slog, zap, zerolog or none")
+	generateCmd.Flags().String("migrations", "none", "Adapter to scaffold migrations for: postgres, mariadb, sql or none, they're applied on startup when migrate.on_startup is set")
 }
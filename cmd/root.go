@@ -1,17 +1,26 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
 
+	"github.com/mahcks/gowizard/pkg/declarative"
 	"github.com/mahcks/gowizard/pkg/generator"
+	"github.com/mahcks/gowizard/pkg/spec"
 	"github.com/mahcks/gowizard/pkg/ui"
 	"github.com/mahcks/gowizard/pkg/utils"
+	"github.com/mgutz/ansi"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var cfgFile string
+var pluginsDir string
 
 var (
 	Version         = "0.1.0"
@@ -28,7 +37,7 @@ var rootCmd = &cobra.Command{
 You can also just skip the wizard... 
 gowizard generate --module github.com/username/module --path /path/to/module --adapter mariadb,redis,mongodb`,
 	Run: func(cmd *cobra.Command, args []string) {
-		gen := generator.NewGenerator()
+		gen := newGenerator()
 		ui := ui.NewUI(gen)
 
 		// Ask for module name
@@ -49,27 +58,69 @@ gowizard generate --module github.com/username/module --path /path/to/module --a
 			return
 		}
 
-		// Prompt for adapters
-		adapters, err := ui.PromptForAdapters()
-		if err != nil {
-			return
-		}
+		gen.Configure(
+			generator.WithModule(module),
+			generator.WithGoVersion(goVersion),
+			generator.WithPath(path),
+		)
 
-		// Propt for services
-		services, err := ui.PromptForServices()
-		if err != nil {
-			return
-		}
+		// Ask again until the adapters and services can be generated together
+		var adapters []string
+		var chosenFlavors map[string]string
+		var logger string
+		var migrations string
+		for {
+			// Prompt for adapters
+			adapters, err = ui.PromptForAdapters()
+			if err != nil {
+				return
+			}
 
-		// For each service selected, prompt for the service's adapters
-		chosenFlavors := make(map[string]string, len(services)) // map[service]flavor
-		for _, service := range services {
-			flavor, err := ui.PromptForServiceFlavor(service)
+			// Propt for services
+			services, err := ui.PromptForServices()
 			if err != nil {
 				return
 			}
 
-			chosenFlavors[service] = flavor
+			// For each service selected, prompt for the service's adapters
+			chosenFlavors = make(map[string]string, len(services)) // map[service]flavor
+			for _, service := range services {
+				flavor, err := ui.PromptForServiceFlavor(service)
+				if err != nil {
+					return
+				}
+
+				chosenFlavors[service] = flavor
+			}
+
+			logger, err = ui.PromptForLogger()
+			if err != nil {
+				return
+			}
+
+			// Only asked when one of the adapters has a SQL database, the answer is the adapter
+			migrations, err = ui.PromptForMigrations(adapters)
+			if err != nil {
+				return
+			}
+
+			gen.Configure(
+				generator.WithAdapters(adapters...),
+				generator.WithServices(chosenFlavors),
+				generator.WithLogger(noneToEmpty(logger)),
+				generator.WithMigrations(migrations),
+			)
+
+			// Select the adapters the chosen ones depend on
+			adapters = append(adapters, gen.ResolveRequirements()...)
+
+			err = gen.CheckConstraints()
+			if err == nil {
+				break
+			}
+
+			utils.PrintError("the chosen adapters, services and logger can't be used together:\n%s", err)
+			fmt.Println("Please choose again.")
 		}
 
 		if chosenFlavors == nil || adapters == nil {
@@ -77,37 +128,110 @@ gowizard generate --module github.com/username/module --path /path/to/module --a
 			return
 		}
 
-		gen.SetSettings(module, goVersion, path, adapters, chosenFlavors)
+		// Write the answers out as a spec so the project can be regenerated with `gowizard generate --from`
+		specPath, err := cmd.Flags().GetString("spec-out")
+		if err != nil {
+			utils.PrintError("error getting spec-out flag: %s", err)
+			return
+		}
+
+		if specPath == "" {
+			specPath = filepath.Join(path, "gowizard.yaml")
+		}
+
+		s := &spec.Spec{
+			Module:     module,
+			GoVersion:  goVersion,
+			Path:       path,
+			Adapters:   adapters,
+			Services:   chosenFlavors,
+			Logger:     logger,
+			Migrations: migrations,
+		}
+
+		b, err := s.Marshal(specPath)
+		if err != nil {
+			utils.PrintError("error encoding spec: %s", err)
+			return
+		}
+
+		// A spec in the project is written by the generator, so it's recorded in the manifest and rolled back with the project
+		rel, err := filepath.Rel(path, specPath)
+		inProject := err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+		if inProject {
+			gen.Configure(generator.WithFile(filepath.ToSlash(rel), b))
+		}
 
-		err = gen.Generate()
+		err = gen.Generate(cmd.Context())
 		if err != nil {
 			fmt.Println(err.Error())
 
-			errRollback := gen.Rollback()
-			if errRollback != nil {
-				utils.PrintError("error rolling back: %s", errRollback)
-			}
+			rollback(gen)
 			return
 		}
+
+		if !inProject {
+			err = s.Save(specPath)
+			if err != nil {
+				utils.PrintError("error saving spec: %s", err)
+				return
+			}
+		}
+
+		fmt.Println("Saved project spec to", specPath)
 	},
 }
 
+// newGenerator creates a generator that records the current version of gowizard and prints its progress
+func newGenerator(opts ...generator.Option) *generator.Generator {
+	return generator.NewGenerator(append([]generator.Option{
+		generator.WithVersion(Version),
+		generator.WithEventHandler(printEvent),
+	}, opts...)...)
+}
+
+// printEvent prints the progress of the generator
+func printEvent(event generator.Event) {
+	switch event.Kind {
+	case generator.EventDone:
+		fmt.Println(ansi.Color("Done!", "green+b"), fmt.Sprintf("\033[3m%s\033[0m", utils.GetRandomPhrase()))
+	case generator.EventWarning:
+		fmt.Println(ansi.Color("[!]", "yellow"), ansi.Color(event.Message, "yellow"), ansi.ColorCode("reset"))
+	default:
+		fmt.Println(ansi.Color("[✓]", "green"), ansi.Color(event.Message, "white"), ansi.ColorCode("reset"))
+	}
+}
+
+// rollback undoes the changes of a failed generator run
+func rollback(gen *generator.Generator) {
+	err := gen.Rollback()
+	if err != nil {
+		utils.PrintError("error rolling back: %s", err)
+	}
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	err := rootCmd.Execute()
+	// Interrupting stops the generator between steps, the changes made so far are rolled back
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
 	if err != nil {
 		os.Exit(1)
 	}
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
+	cobra.OnInitialize(initConfig, initPlugins)
 
 	rootCmd.Version = Version
 	rootCmd.SetVersionTemplate(versionTemplate)
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.gowizard.yaml)")
+	rootCmd.PersistentFlags().StringVar(&pluginsDir, "plugins", "", "directory with declarative adapters and flavors (default is $HOME/.gowizard/plugins)")
+	rootCmd.Flags().String("spec-out", "", "Where to write the wizard answers as a spec file (default is gowizard.yaml in the module path)")
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -133,3 +257,37 @@ func initConfig() {
 		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	}
 }
+
+// initPlugins registers the declarative adapters and flavors, each one is a folder with a plugin.yaml
+func initPlugins() {
+	dir := pluginsDir
+	if dir == "" {
+		dir = viper.GetString("plugins")
+	}
+
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return
+		}
+
+		dir = filepath.Join(home, ".gowizard", "plugins")
+		if _, err := os.Stat(dir); err != nil {
+			return
+		}
+	}
+
+	modules, err := declarative.LoadAll(afero.NewOsFs(), dir)
+	if err != nil {
+		utils.PrintError("error loading plugins from %s: %s", dir, err)
+		os.Exit(1)
+	}
+
+	for _, module := range modules {
+		err = declarative.Register(module)
+		if err != nil {
+			utils.PrintError("%s", err)
+			os.Exit(1)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
+
+	"github.com/mahcks/gowizard/pkg/dryrun"
 	"github.com/mahcks/gowizard/pkg/generator"
 	"github.com/mahcks/gowizard/pkg/ui"
 	"github.com/mahcks/gowizard/pkg/utils"
@@ -12,7 +16,7 @@ var templateCmd = &cobra.Command{
 	Use:   "template",
 	Short: "Use a predefined template to generate a project.",
 	Run: func(cmd *cobra.Command, args []string) {
-		gen := generator.NewGenerator()
+		gen := newGenerator()
 		ui := ui.NewUI(gen)
 
 		// Ask for module name
@@ -54,10 +58,42 @@ var templateCmd = &cobra.Command{
 			}
 		}
 
-		gen.SetSettings(module, goVersion, path, []string{}, nil)
-		err = gen.UseTemplate(template, isCustom)
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			utils.PrintError("error getting dry-run flag: %s", err)
+			return
+		}
+
+		// A dry run writes the project to memory and prints it instead
+		var dryRunFs *dryrun.Fs
+		if dryRun {
+			path, err = filepath.Abs(path)
+			if err != nil {
+				utils.PrintError("error getting absolute path: %s", err)
+				return
+			}
+
+			dryRunFs = dryrun.NewFs()
+			gen.Configure(generator.WithFs(dryRunFs), generator.WithDryRun(true))
+		}
+
+		gen.Configure(
+			generator.WithModule(module),
+			generator.WithGoVersion(goVersion),
+			generator.WithPath(path),
+		)
+		err = gen.UseTemplate(cmd.Context(), template, isCustom)
 		if err != nil {
 			utils.PrintError("error generating template: %s", err.Error())
+			rollback(gen)
+			return
+		}
+
+		if dryRun {
+			err = dryRunFs.Print(os.Stdout, path)
+			if err != nil {
+				utils.PrintError("error printing dry run: %s", err)
+			}
 		}
 	},
 }
@@ -66,4 +102,5 @@ func init() {
 	rootCmd.AddCommand(templateCmd)
 
 	templateCmd.Flags().BoolP("custom", "c", false, "Use a custom template, this will let to specify a Go version and module name but won't have any extra setup.")
+	templateCmd.Flags().Bool("dry-run", false, "Print what would be generated without cloning the template or writing anything to disk")
 }
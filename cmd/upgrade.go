@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mahcks/gowizard/pkg/generator"
+	"github.com/mahcks/gowizard/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// upgradeCmd represents the upgrade command
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrade a generated project to what the current version of gowizard generates.",
+	Long: `Re-runs the generator with the settings recorded in .gowizard.lock and three-way merges the output into the project.
+
+Files that weren't changed since they were generated are replaced, changed files are merged with the new output.
+Files that can't be merged cleanly get conflict markers.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		gen, err := loadProject(cmd)
+		if err != nil {
+			utils.PrintError("%s", err)
+			return
+		}
+
+		results, err := gen.Upgrade(cmd.Context())
+		if err != nil {
+			utils.PrintError("error upgrading project: %s", err)
+			rollback(gen)
+			return
+		}
+
+		conflicts := 0
+		for _, result := range results {
+			if result.Status == generator.UpgradeUnchanged {
+				continue
+			}
+
+			if result.Status == generator.UpgradeConflict {
+				conflicts++
+			}
+
+			if result.Note != "" {
+				fmt.Printf("%-10s %s (%s)\n", result.Status, result.File, result.Note)
+			} else {
+				fmt.Printf("%-10s %s\n", result.Status, result.File)
+			}
+		}
+
+		if conflicts != 0 {
+			utils.PrintError("%d file(s) have conflicts, resolve them and run `go mod tidy`", conflicts)
+			return
+		}
+
+		fmt.Println("Upgraded to gowizard v" + Version)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(upgradeCmd)
+
+	upgradeCmd.Flags().StringP("path", "p", "./", "Path to the generated module")
+}
@@ -4,6 +4,7 @@ import (
 	j "github.com/dave/jennifer/jen"
 	"github.com/mahcks/gowizard/pkg/domain"
 	"github.com/mahcks/gowizard/pkg/utils"
+	"github.com/spf13/afero"
 )
 
 type MariaDBAdapter struct {
@@ -53,35 +54,60 @@ func (adp *MariaDBAdapter) ConfigGo() *j.Statement {
 }
 
 // AppInit is the code that will be added to the START internal/app/app.go Run() function
-func (adp *MariaDBAdapter) AppInit(module string) []j.Code {
+func (adp *MariaDBAdapter) AppInit(ns domain.Namespace) []j.Code {
 	return []j.Code{
-		j.List(j.Id("mdb"), j.Err()).Op(":=").Qual(module+"/pkg/mariadb", "New").Params(j.Id("cfg.MariaDB.Host"), j.Id("cfg.MariaDB.Port"), j.Id("cfg.MariaDB.Database"), j.Id("cfg.MariaDB.Username"), j.Id("cfg.MariaDB.Password")).Op(";"),
+		j.List(j.Id(ns.Id("DB")), j.Err()).Op(":=").Qual(ns.Path(), "New").Params(j.Id("cfg.MariaDB.Host"), j.Id("cfg.MariaDB.Port"), j.Id("cfg.MariaDB.Database"), j.Id("cfg.MariaDB.Username"), j.Id("cfg.MariaDB.Password")).Op(";"),
 		j.If(j.Err().Op("!=").Nil()).Block(
-			j.Qual("fmt", "Println").Call(j.Lit("error connecting to mariadb"), j.Err()),
+			ns.Log("Error", "error connecting to mariadb", j.Lit("error"), j.Err()),
 		),
 		j.Line(),
 		j.Line(),
-		j.Qual("fmt", "Println").Call(j.Lit("connected to mariadb")),
+		ns.Log("Info", "connected to mariadb"),
 		j.Line(),
 	}
 }
 
 // AppSelect - Each AppSelect branch is apart of a bigger switch statement that's in the internal/app/app.go Run() function
-func (adp *MariaDBAdapter) AppSelect(module string) j.Code {
+func (adp *MariaDBAdapter) AppSelect(ns domain.Namespace) j.Code {
 
 	return nil
 }
 
 // AppShutdown is the code that will be added to the END internal/app/app.go Run() function
-func (adp *MariaDBAdapter) AppShutdown(module string) []j.Code {
+func (adp *MariaDBAdapter) AppShutdown(ns domain.Namespace) []j.Code {
 	return []j.Code{
-		j.Id("mdb").Dot("Close").Call(),
+		j.Id(ns.Id("DB")).Dot("Close").Call(),
 	}
 }
 
-// Service is the code that will be added to its own `pkg` folder
-func (adp *MariaDBAdapter) Service(module, path string) *j.File {
-	f := j.NewFilePathName(module+"/pkg/mariadb", "mariadb")
+// Constraints - what the adapter needs from the rest of the project
+func (adp *MariaDBAdapter) Constraints() domain.Constraints {
+	return domain.Constraints{
+		Packages: []string{"pkg/" + adp.name},
+	}
+}
+
+// MigrateDriver - the mysql driver of golang-migrate
+func (adp *MariaDBAdapter) MigrateDriver() string {
+	return "github.com/golang-migrate/migrate/v4/database/mysql"
+}
+
+// MigrateURL is the database URL the migrations are applied with
+func (adp *MariaDBAdapter) MigrateURL() *j.Statement {
+	return mysqlMigrateURL("cfg.MariaDB")
+}
+
+// InitialMigration returns the up and down SQL of the first migration
+func (adp *MariaDBAdapter) InitialMigration() (string, string) {
+	return mysqlInitialUp, initialDown
+}
+
+// Service is the code that will be added to the package of its namespace
+func (adp *MariaDBAdapter) Service(fs afero.Fs, ns domain.Namespace, path string) error {
+	f := j.NewFilePathName(ns.Path(), ns.PackageName())
+
+	// The adapter registers the driver it opens, so it works no matter how it was added to the project
+	f.Anon("github.com/go-sql-driver/mysql")
 
 	// Service struct
 	sStruct := j.Type().Id("MariaDB").Struct(
@@ -123,20 +149,17 @@ func (adp *MariaDBAdapter) Service(module, path string) *j.File {
 
 	// Close function
 	f.Func().Params(j.Id("m").Op("*").Id("MariaDB")).Id("Close").Params().Error().Block(
-		j.If(
-			j.Id("m").Dot("DB").Op("!=").Nil().Block(
-				j.Id("m").Dot("DB").Dot("Close").Call(),
-			),
+		j.If(j.Id("m").Dot("DB").Op("!=").Nil()).Block(
+			j.Return(j.Id("m").Dot("DB").Dot("Close").Call()),
 		),
 		j.Line(),
 		j.Return(j.Nil()),
 	)
 
-	err := f.Save(path + "/pkg/" + adp.name + "/adapter.go")
+	err := utils.SaveFile(fs, f, path+"/"+ns.Package+"/adapter.go")
 	if err != nil {
-		utils.PrintError("error saving file: %s", err)
-		return nil
+		return &domain.GenerateError{Module: adp.name, File: ns.Package + "/adapter.go", Err: err}
 	}
 
-	return f
+	return nil
 }
@@ -0,0 +1,182 @@
+package adapters
+
+import (
+	"os"
+
+	j "github.com/dave/jennifer/jen"
+	"github.com/spf13/afero"
+
+	"github.com/mahcks/gowizard/pkg/domain"
+	"github.com/mahcks/gowizard/pkg/utils"
+)
+
+// postgresInitialUp is the first migration of a PostgreSQL database
+const postgresInitialUp = `-- Initial migration, replace the example table with the tables of the project
+CREATE TABLE IF NOT EXISTS examples (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+`
+
+// mysqlInitialUp is the first migration of a MariaDB or MySQL database
+const mysqlInitialUp = `-- Initial migration, replace the example table with the tables of the project
+CREATE TABLE IF NOT EXISTS examples (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+// initialDown undoes the first migration
+const initialDown = `DROP TABLE IF EXISTS examples;
+`
+
+// mysqlMigrateURL is the URL of the mysql driver of golang-migrate, cfg is the config section of the adapter, i.e. cfg.MariaDB
+func mysqlMigrateURL(cfg string) *j.Statement {
+	return j.Qual("fmt", "Sprintf").Call(
+		j.Lit("mysql://%s:%s@tcp(%s:%s)/%s?multiStatements=true"),
+		j.Id(cfg+".Username"), j.Id(cfg+".Password"), j.Id(cfg+".Host"), j.Id(cfg+".Port"), j.Id(cfg+".Database"),
+	)
+}
+
+// MigrateModule scaffolds golang-migrate migrations for the database of an adapter
+// It isn't registered, the generator creates it for the adapter the migrations setting of a project names
+type MigrateModule struct {
+	name     string // name of the module
+	adapter  string // name of the adapter of the database
+	database domain.MigrationsI
+}
+
+// NewMigrateModule - Migrations for the database of the adapter, i.e. postgres
+func NewMigrateModule(adapter string, database domain.MigrationsI) domain.ModuleI {
+	return &MigrateModule{
+		name:     "migrate",
+		adapter:  adapter,
+		database: database,
+	}
+}
+
+// GetName returns the name of the module
+func (mod *MigrateModule) GetName() string {
+	return mod.name
+}
+
+// GetDisplayName - what will be displayed in the CLI when prompted
+func (mod *MigrateModule) GetDisplayName() string {
+	return "Migrations"
+}
+
+// ConfigYAML is the configuration of the module in YAML format, the migrations aren't applied on startup until it's turned on
+func (mod *MigrateModule) ConfigYAML() map[string]interface{} {
+	return map[string]interface{}{
+		"migrate": map[string]interface{}{
+			"on_startup": false,
+			"source":     "file://migrations",
+		},
+	}
+}
+
+// ConfigGo is the configuration of the module in Go format
+func (mod *MigrateModule) ConfigGo() *j.Statement {
+	return j.Id("Migrate").Struct(
+		j.Id("OnStartup").Bool().Tag(map[string]string{"mapstructure": "on_startup", "json": "on_startup"}),
+		j.Id("Source").String().Tag(map[string]string{"mapstructure": "source", "json": "source"}),
+	).Tag(map[string]string{"mapstructure": "migrate", "json": "migrate"})
+}
+
+// AppInit is the code that will be added to the START internal/app/app.go Run() function
+func (mod *MigrateModule) AppInit(ns domain.Namespace) []j.Code {
+	return []j.Code{
+		j.If(j.Id("cfg.Migrate.OnStartup")).Block(
+			j.Err().Op(":=").Qual(ns.Path(), "Up").Call(j.Id("cfg.Migrate.Source"), mod.database.MigrateURL()),
+			j.If(j.Err().Op("!=").Nil()).Block(
+				ns.Log("Error", "error applying migrations", j.Lit("error"), j.Err()),
+			).Else().Block(
+				ns.Log("Info", "applied migrations"),
+			),
+		),
+	}
+}
+
+// AppSelect - Each AppSelect branch is apart of a bigger switch statement that's in the internal/app/app.go Run() function
+func (mod *MigrateModule) AppSelect(ns domain.Namespace) j.Code {
+	return nil
+}
+
+// AppShutdown is the code that will be added to the END internal/app/app.go Run() function
+func (mod *MigrateModule) AppShutdown(ns domain.Namespace) []j.Code {
+	return nil
+}
+
+// Constraints - what the module needs from the rest of the project
+func (mod *MigrateModule) Constraints() domain.Constraints {
+	return domain.Constraints{
+		Requires: []string{mod.adapter},
+		Packages: []string{"pkg/migrate", "migrations"},
+	}
+}
+
+// Service is the code that will be added to the package of its namespace and the initial migration in migrations
+func (mod *MigrateModule) Service(fs afero.Fs, ns domain.Namespace, path string) error {
+	err := fs.MkdirAll(path+"/migrations", os.ModePerm)
+	if err != nil {
+		return &domain.GenerateError{Module: mod.name, File: "migrations", Err: err}
+	}
+
+	up, down := mod.database.InitialMigration()
+	migrations := []struct {
+		name    string
+		content string
+	}{
+		{"000001_init.up.sql", up},
+		{"000001_init.down.sql", down},
+	}
+
+	for _, m := range migrations {
+		err = afero.WriteFile(fs, path+"/migrations/"+m.name, []byte(m.content), 0644)
+		if err != nil {
+			return &domain.GenerateError{Module: mod.name, File: "migrations/" + m.name, Err: err}
+		}
+	}
+
+	f := j.NewFilePathName(ns.Path(), ns.PackageName())
+	f.ImportAlias("github.com/golang-migrate/migrate/v4", "gomigrate")
+	f.Anon(mod.database.MigrateDriver(), "github.com/golang-migrate/migrate/v4/source/file")
+
+	f.Comment("Up - Applies the migrations of the source that haven't been applied to the database yet, i.e. file://migrations")
+	f.Func().Id("Up").Params(j.List(j.Id("sourceURL"), j.Id("databaseURL")).String()).Error().Block(
+		j.List(j.Id("m"), j.Err()).Op(":=").Qual("github.com/golang-migrate/migrate/v4", "New").Call(j.Id("sourceURL"), j.Id("databaseURL")),
+		j.If(j.Err().Op("!=").Nil()).Block(
+			j.Return(j.Err()),
+		),
+		j.Defer().Id("m").Dot("Close").Call(),
+		j.Line(),
+		j.Err().Op("=").Id("m").Dot("Up").Call(),
+		j.If(j.Qual("errors", "Is").Call(j.Err(), j.Qual("github.com/golang-migrate/migrate/v4", "ErrNoChange"))).Block(
+			j.Return(j.Nil()),
+		),
+		j.Line(),
+		j.Return(j.Err()),
+	)
+
+	f.Line()
+
+	f.Comment("Down - Rolls back the last applied migration")
+	f.Func().Id("Down").Params(j.List(j.Id("sourceURL"), j.Id("databaseURL")).String()).Error().Block(
+		j.List(j.Id("m"), j.Err()).Op(":=").Qual("github.com/golang-migrate/migrate/v4", "New").Call(j.Id("sourceURL"), j.Id("databaseURL")),
+		j.If(j.Err().Op("!=").Nil()).Block(
+			j.Return(j.Err()),
+		),
+		j.Defer().Id("m").Dot("Close").Call(),
+		j.Line(),
+		j.Return(j.Id("m").Dot("Steps").Call(j.Lit(-1))),
+	)
+
+	err = utils.SaveFile(fs, f, path+"/"+ns.Package+"/migrate.go")
+	if err != nil {
+		return &domain.GenerateError{Module: mod.name, File: ns.Package + "/migrate.go", Err: err}
+	}
+
+	return nil
+}
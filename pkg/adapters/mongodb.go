@@ -4,6 +4,7 @@ import (
 	j "github.com/dave/jennifer/jen"
 	"github.com/mahcks/gowizard/pkg/domain"
 	"github.com/mahcks/gowizard/pkg/utils"
+	"github.com/spf13/afero"
 )
 
 type MongoDBAdapter struct {
@@ -45,36 +46,43 @@ func (adp *MongoDBAdapter) ConfigGo() *j.Statement {
 }
 
 // AppInit is the code that will be added to the END internal/app/app.go Run() function
-func (adp *MongoDBAdapter) AppInit(module string) []j.Code {
+func (adp *MongoDBAdapter) AppInit(ns domain.Namespace) []j.Code {
 	return []j.Code{
 		j.Line(),
-		j.List(j.Id("mongodb"), j.Err()).Op(":=").Qual(module+"/pkg/mongodb", "New").Params(j.Id("gCtx"), j.Id("cfg.MongoDB.URI")).Op(";"),
+		j.List(j.Id(ns.Id("client")), j.Err()).Op(":=").Qual(ns.Path(), "New").Params(j.Id("gCtx"), j.Id("cfg.MongoDB.URI")).Op(";"),
 		j.If(j.Err().Op("!=").Nil()).Block(
-			j.Qual("fmt", "Println").Call(j.Lit("error connecting to mongodb"), j.Err()),
+			ns.Log("Error", "error connecting to mongodb", j.Lit("error"), j.Err()),
 		),
 		j.Line(),
 		j.Line(),
-		j.Qual("fmt", "Println").Call(j.Lit("connected to mongodb")),
+		ns.Log("Info", "connected to mongodb"),
 		j.Line(),
 	}
 }
 
 // AppSelect - Each AppSelect branch is apart of a bigger switch statement that's in the internal/app/app.go Run() function
-func (adp *MongoDBAdapter) AppSelect(module string) j.Code {
+func (adp *MongoDBAdapter) AppSelect(ns domain.Namespace) j.Code {
 
 	return nil
 }
 
 // AppShutdown is the code that will be added to the END internal/app/app.go Run() function
-func (adp *MongoDBAdapter) AppShutdown(module string) []j.Code {
+func (adp *MongoDBAdapter) AppShutdown(ns domain.Namespace) []j.Code {
 	return []j.Code{
-		j.Id("mongodb").Dot("Close").Call(j.Id("gCtx")),
+		j.Id(ns.Id("client")).Dot("Close").Call(j.Id("gCtx")),
 	}
 }
 
-// Service is the code that will be added to its own `pkg` folder
-func (adp *MongoDBAdapter) Service(module, path string) *j.File {
-	f := j.NewFilePathName(module+"/pkg/mongodb", "mongodb")
+// Constraints - what the adapter needs from the rest of the project
+func (adp *MongoDBAdapter) Constraints() domain.Constraints {
+	return domain.Constraints{
+		Packages: []string{"pkg/" + adp.name},
+	}
+}
+
+// Service is the code that will be added to the package of its namespace
+func (adp *MongoDBAdapter) Service(fs afero.Fs, ns domain.Namespace, path string) error {
+	f := j.NewFilePathName(ns.Path(), ns.PackageName())
 
 	// Service struct
 	sStruct := j.Type().Id("MongoDB").Struct(
@@ -118,11 +126,10 @@ func (adp *MongoDBAdapter) Service(module, path string) *j.File {
 		),
 	)
 
-	err := f.Save(path + "/pkg/" + adp.name + "/adapter.go")
+	err := utils.SaveFile(fs, f, path+"/"+ns.Package+"/adapter.go")
 	if err != nil {
-		utils.PrintError("error saving file: %s", err)
-		return nil
+		return &domain.GenerateError{Module: adp.name, File: ns.Package + "/adapter.go", Err: err}
 	}
 
-	return f
+	return nil
 }
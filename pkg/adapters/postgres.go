@@ -4,6 +4,7 @@ import (
 	j "github.com/dave/jennifer/jen"
 	"github.com/mahcks/gowizard/pkg/domain"
 	"github.com/mahcks/gowizard/pkg/utils"
+	"github.com/spf13/afero"
 )
 
 type PostgresAdapter struct {
@@ -47,35 +48,57 @@ func (adp *PostgresAdapter) ConfigGo() *j.Statement {
 }
 
 // AppInit is the code that will be added to the START internal/app/app.go Run() function
-func (adp *PostgresAdapter) AppInit(module string) []j.Code {
+func (adp *PostgresAdapter) AppInit(ns domain.Namespace) []j.Code {
 	return []j.Code{
-		j.List(j.Id("pg"), j.Err()).Op(":=").Qual(module+"/pkg/postgres", "New").Params(j.Id("gCtx"), j.Id("cfg.Postgres.URL")),
+		j.List(j.Id(ns.Id("pool")), j.Err()).Op(":=").Qual(ns.Path(), "New").Params(j.Id("gCtx"), j.Id("cfg.Postgres.URL")),
 		j.Line(),
 		j.If(j.Err().Op("!=").Nil()).Block(
-			j.Qual("fmt", "Println").Call(j.Lit("error connecting to postgres"), j.Err()),
+			ns.Log("Error", "error connecting to postgres", j.Lit("error"), j.Err()),
 		),
 		j.Line(),
 		j.Line(),
-		j.Qual("fmt", "Println").Call(j.Lit("connected to mongodb")),
+		ns.Log("Info", "connected to postgres"),
 		j.Line(),
 	}
 }
 
 // AppSelect - Each AppSelect branch is apart of a bigger switch statement that's in the internal/app/app.go Run() function
-func (adp *PostgresAdapter) AppSelect(module string) j.Code {
+func (adp *PostgresAdapter) AppSelect(ns domain.Namespace) j.Code {
 	return nil
 }
 
 // AppShutdown is the code that will be added to the END internal/app/app.go Run() function
-func (adp *PostgresAdapter) AppShutdown(module string) []j.Code {
+func (adp *PostgresAdapter) AppShutdown(ns domain.Namespace) []j.Code {
 	return []j.Code{
-		j.Id("pg").Dot("Close").Call(),
+		j.Id(ns.Id("pool")).Dot("Close").Call(),
 	}
 }
 
-// Service is the code that will be added to its own `pkg` folder
-func (adp *PostgresAdapter) Service(module, path string) *j.File {
-	f := j.NewFilePathName(module+"/pkg/postgres", "postgres")
+// Constraints - what the adapter needs from the rest of the project
+func (adp *PostgresAdapter) Constraints() domain.Constraints {
+	return domain.Constraints{
+		Packages: []string{"pkg/" + adp.name},
+	}
+}
+
+// MigrateDriver - golang-migrate connects with lib/pq, so the URL of the config can be used as it is
+func (adp *PostgresAdapter) MigrateDriver() string {
+	return "github.com/golang-migrate/migrate/v4/database/postgres"
+}
+
+// MigrateURL is the database URL the migrations are applied with
+func (adp *PostgresAdapter) MigrateURL() *j.Statement {
+	return j.Id("cfg.Postgres.URL")
+}
+
+// InitialMigration returns the up and down SQL of the first migration
+func (adp *PostgresAdapter) InitialMigration() (string, string) {
+	return postgresInitialUp, initialDown
+}
+
+// Service is the code that will be added to the package of its namespace
+func (adp *PostgresAdapter) Service(fs afero.Fs, ns domain.Namespace, path string) error {
+	f := j.NewFilePathName(ns.Path(), ns.PackageName())
 
 	// Service struct
 	sStruct := j.Type().Id("Postgres").Struct(
@@ -129,11 +152,10 @@ func (adp *PostgresAdapter) Service(module, path string) *j.File {
 		),
 	)
 
-	err := f.Save(path + "/pkg/" + adp.name + "/adapter.go")
+	err := utils.SaveFile(fs, f, path+"/"+ns.Package+"/adapter.go")
 	if err != nil {
-		utils.PrintError("error saving file: %s", err)
-		return nil
+		return &domain.GenerateError{Module: adp.name, File: ns.Package + "/adapter.go", Err: err}
 	}
 
-	return f
+	return nil
 }
@@ -4,6 +4,7 @@ import (
 	j "github.com/dave/jennifer/jen"
 	"github.com/mahcks/gowizard/pkg/domain"
 	"github.com/mahcks/gowizard/pkg/utils"
+	"github.com/spf13/afero"
 )
 
 type RedisAdapter struct {
@@ -49,36 +50,43 @@ func (adp *RedisAdapter) ConfigGo() *j.Statement {
 }
 
 // AppInit is the code that will be added to the START internal/app/app.go Run() function
-func (adp *RedisAdapter) AppInit(module string) []j.Code {
+func (adp *RedisAdapter) AppInit(ns domain.Namespace) []j.Code {
 	return []j.Code{
 		j.Line(),
-		j.List(j.Id("redisClient"), j.Err()).Op(":=").Qual(module+"/pkg/redis", "New").Params(j.Id("gCtx"), j.Id("cfg.Redis.Host"), j.Id("cfg.Redis.Port"), j.Id("cfg.Redis.Password")).Op(";"),
+		j.List(j.Id(ns.Id("client")), j.Err()).Op(":=").Qual(ns.Path(), "New").Params(j.Id("gCtx"), j.Id("cfg.Redis.Host"), j.Id("cfg.Redis.Port"), j.Id("cfg.Redis.Password")).Op(";"),
 		j.If(j.Err().Op("!=").Nil()).Block(
-			j.Qual("fmt", "Println").Call(j.Lit("error connecting to redis"), j.Err()),
+			ns.Log("Error", "error connecting to redis", j.Lit("error"), j.Err()),
 		),
 		j.Line(),
 		j.Line(),
-		j.Qual("fmt", "Println").Call(j.Lit("connected to redis")),
+		ns.Log("Info", "connected to redis"),
 		j.Line(),
 	}
 }
 
 // AppSelect - Each AppSelect branch is apart of a bigger switch statement that's in the internal/app/app.go Run() function
-func (adp *RedisAdapter) AppSelect(module string) j.Code {
+func (adp *RedisAdapter) AppSelect(ns domain.Namespace) j.Code {
 
 	return nil
 }
 
 // AppShutdown is the code that will be added to the END internal/app/app.go Run() function
-func (adp *RedisAdapter) AppShutdown(module string) []j.Code {
+func (adp *RedisAdapter) AppShutdown(ns domain.Namespace) []j.Code {
 	return []j.Code{
-		j.Id("redisClient").Dot("Close").Call(),
+		j.Id(ns.Id("client")).Dot("Close").Call(),
 	}
 }
 
-// Service is the code that will be added to its own `pkg` folder
-func (adp *RedisAdapter) Service(module, path string) *j.File {
-	f := j.NewFilePathName(module+"/pkg/redis", "redis")
+// Constraints - what the adapter needs from the rest of the project
+func (adp *RedisAdapter) Constraints() domain.Constraints {
+	return domain.Constraints{
+		Packages: []string{"pkg/" + adp.name},
+	}
+}
+
+// Service is the code that will be added to the package of its namespace
+func (adp *RedisAdapter) Service(fs afero.Fs, ns domain.Namespace, path string) error {
+	f := j.NewFilePathName(ns.Path(), ns.PackageName())
 
 	// Service struct
 	sStruct := j.Type().Id("Redis").Struct(
@@ -134,11 +142,10 @@ func (adp *RedisAdapter) Service(module, path string) *j.File {
 		j.Return(j.Nil()),
 	)
 
-	err := f.Save(path + "/pkg/" + adp.name + "/adapter.go")
+	err := utils.SaveFile(fs, f, path+"/"+ns.Package+"/adapter.go")
 	if err != nil {
-		utils.PrintError("error saving file: %s", err)
-		return nil
+		return &domain.GenerateError{Module: adp.name, File: ns.Package + "/adapter.go", Err: err}
 	}
 
-	return f
+	return nil
 }
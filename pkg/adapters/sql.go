@@ -4,6 +4,7 @@ import (
 	j "github.com/dave/jennifer/jen"
 	"github.com/mahcks/gowizard/pkg/domain"
 	"github.com/mahcks/gowizard/pkg/utils"
+	"github.com/spf13/afero"
 )
 
 type SQLAdapter struct {
@@ -53,35 +54,60 @@ func (adp *SQLAdapter) ConfigGo() *j.Statement {
 }
 
 // AppInit is the code that will be added to the START internal/app/app.go Run() function
-func (adp *SQLAdapter) AppInit(module string) []j.Code {
+func (adp *SQLAdapter) AppInit(ns domain.Namespace) []j.Code {
 	return []j.Code{
-		j.List(j.Id("db"), j.Err()).Op(":=").Qual(module+"/pkg/sql", "New").Params(j.Id("cfg.SQL.Host"), j.Id("cfg.SQL.Port"), j.Id("cfg.SQL.Database"), j.Id("cfg.SQL.Username"), j.Id("cfg.SQL.Password")).Op(";"),
+		j.List(j.Id(ns.Id("DB")), j.Err()).Op(":=").Qual(ns.Path(), "New").Params(j.Id("cfg.SQL.Host"), j.Id("cfg.SQL.Port"), j.Id("cfg.SQL.Database"), j.Id("cfg.SQL.Username"), j.Id("cfg.SQL.Password")).Op(";"),
 		j.If(j.Err().Op("!=").Nil()).Block(
-			j.Qual("fmt", "Println").Call(j.Lit("error connecting to sql"), j.Err()),
+			ns.Log("Error", "error connecting to sql", j.Lit("error"), j.Err()),
 		),
 		j.Line(),
 		j.Line(),
-		j.Qual("fmt", "Println").Call(j.Lit("connected to sql")),
+		ns.Log("Info", "connected to sql"),
 		j.Line(),
 	}
 }
 
 // AppSelect - Each AppSelect branch is apart of a bigger switch statement that's in the internal/app/app.go Run() function
-func (adp *SQLAdapter) AppSelect(module string) j.Code {
+func (adp *SQLAdapter) AppSelect(ns domain.Namespace) j.Code {
 
 	return nil
 }
 
 // AppShutdown is the code that will be added to the END internal/app/app.go Run() function
-func (adp *SQLAdapter) AppShutdown(module string) []j.Code {
+func (adp *SQLAdapter) AppShutdown(ns domain.Namespace) []j.Code {
 	return []j.Code{
-		j.Id("db").Dot("Close").Call(),
+		j.Id(ns.Id("DB")).Dot("Close").Call(),
 	}
 }
 
-// Service is the code that will be added to its own `pkg` folder
-func (adp *SQLAdapter) Service(module, path string) *j.File {
-	f := j.NewFilePathName(module+"/pkg/sql", "sql")
+// Constraints - what the adapter needs from the rest of the project
+func (adp *SQLAdapter) Constraints() domain.Constraints {
+	return domain.Constraints{
+		Packages: []string{"pkg/" + adp.name},
+	}
+}
+
+// MigrateDriver - the mysql driver of golang-migrate
+func (adp *SQLAdapter) MigrateDriver() string {
+	return "github.com/golang-migrate/migrate/v4/database/mysql"
+}
+
+// MigrateURL is the database URL the migrations are applied with
+func (adp *SQLAdapter) MigrateURL() *j.Statement {
+	return mysqlMigrateURL("cfg.SQL")
+}
+
+// InitialMigration returns the up and down SQL of the first migration
+func (adp *SQLAdapter) InitialMigration() (string, string) {
+	return mysqlInitialUp, initialDown
+}
+
+// Service is the code that will be added to the package of its namespace
+func (adp *SQLAdapter) Service(fs afero.Fs, ns domain.Namespace, path string) error {
+	f := j.NewFilePathName(ns.Path(), ns.PackageName())
+
+	// The adapter registers the driver it opens, so it works no matter how it was added to the project
+	f.Anon("github.com/go-sql-driver/mysql")
 
 	// Service struct
 	sStruct := j.Type().Id("SQL").Struct(
@@ -123,20 +149,17 @@ func (adp *SQLAdapter) Service(module, path string) *j.File {
 
 	// Close function
 	f.Func().Params(j.Id("m").Op("*").Id("SQL")).Id("Close").Params().Error().Block(
-		j.If(
-			j.Id("m").Dot("DB").Op("!=").Nil().Block(
-				j.Id("m").Dot("DB").Dot("Close").Call(),
-			),
+		j.If(j.Id("m").Dot("DB").Op("!=").Nil()).Block(
+			j.Return(j.Id("m").Dot("DB").Dot("Close").Call()),
 		),
 		j.Line(),
 		j.Return(j.Nil()),
 	)
 
-	err := f.Save(path + "/pkg/" + adp.name + "/adapter.go")
+	err := utils.SaveFile(fs, f, path+"/"+ns.Package+"/adapter.go")
 	if err != nil {
-		utils.PrintError("error saving file: %s", err)
-		return nil
+		return &domain.GenerateError{Module: adp.name, File: ns.Package + "/adapter.go", Err: err}
 	}
 
-	return f
+	return nil
 }
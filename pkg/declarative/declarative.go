@@ -0,0 +1,519 @@
+package declarative
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	j "github.com/dave/jennifer/jen"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+
+	"github.com/mahcks/gowizard/pkg/domain"
+	"github.com/mahcks/gowizard/pkg/registry"
+)
+
+// ManifestFile is the name of the manifest in a plugin directory
+const ManifestFile = "plugin.yaml"
+
+const (
+	KindAdapter = "adapter" // The plugin is an adapter, i.e. memcached
+	KindFlavor  = "flavor"  // The plugin is a flavor of an existing service, i.e. rest
+)
+
+// Manifest describes an adapter or flavor without any Go code, see plugin.yaml in testdata for an example
+type Manifest struct {
+	Kind        string `yaml:"kind"`                  // adapter or flavor
+	Service     string `yaml:"service,omitempty"`     // Service the flavor belongs to, i.e. rest
+	Name        string `yaml:"name"`                  // Name used in flags and specs, i.e. memcached
+	DisplayName string `yaml:"display_name"`          // What will be displayed in the CLI when prompted
+	Description string `yaml:"description,omitempty"` // Description of a flavor
+	Config      Config `yaml:"config,omitempty"`      // Keys added to config.go and config.yaml
+	Init        string `yaml:"init,omitempty"`        // Snippet added to the START of the Run() function
+	Select      string `yaml:"select,omitempty"`      // Select branch added to the Run() function
+	Shutdown    string `yaml:"shutdown,omitempty"`    // Snippet added to the END of the Run() function
+	Package     string `yaml:"package,omitempty"`     // Folder of the package the plugin generates, defaults to pkg/<name>
+	Files       []File `yaml:"files,omitempty"`       // Files generated in the project
+
+	Requires  []string `yaml:"requires,omitempty"`   // Adapters that have to be enabled as well
+	Conflicts []string `yaml:"conflicts,omitempty"`  // Adapters that can't be enabled at the same time
+	GoVersion string   `yaml:"go_version,omitempty"` // Minimum Go version of the generated module
+}
+
+// Config is the section the plugin adds to config.go and config.yaml
+type Config struct {
+	Key    string  `yaml:"key,omitempty"` // Key in config.yaml, defaults to the name of the plugin
+	Fields []Field `yaml:"fields"`
+}
+
+// Field is a single config key and its default value
+type Field struct {
+	Name    string      `yaml:"name"`              // Key in config.yaml, i.e. max_conns
+	Type    string      `yaml:"type,omitempty"`    // string, int or bool, defaults to string
+	Default interface{} `yaml:"default,omitempty"` // Value written to config.yaml
+}
+
+// File is a text/template rendered to a file of the project
+type File struct {
+	Path     string `yaml:"path"`     // Path relative to the project, a template as well, i.e. {{.Package}}/adapter.go
+	Template string `yaml:"template"` // Path of the template relative to the plugin directory
+}
+
+// Data is what snippets and file templates are executed with
+// The namespace gives the identifiers and the package of the plugin, i.e. {{.Id "client"}} and {{.Package}}
+type Data struct {
+	domain.Namespace
+	Config string // Name of the config struct field, i.e. cfg.{{.Config}}.Host
+}
+
+// Module is an adapter or flavor loaded from a plugin directory, it implements domain.ModuleI and domain.FlavorI
+type Module struct {
+	manifest Manifest
+	init     *template.Template
+	sel      *template.Template
+	shutdown *template.Template
+	files    []file
+}
+
+// file is a parsed File, both its path and content are templates
+type file struct {
+	path    *template.Template
+	content *template.Template
+}
+
+// Load reads the manifest and templates of the plugin in dir
+func Load(fsys afero.Fs, dir string) (*Module, error) {
+	b, err := afero.ReadFile(fsys, filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Module{}
+	err = yaml.UnmarshalStrict(b, &m.manifest)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %s", filepath.Join(dir, ManifestFile), err)
+	}
+
+	err = m.manifest.validate()
+	if err != nil {
+		return nil, fmt.Errorf("error in %s: %s", filepath.Join(dir, ManifestFile), err)
+	}
+
+	m.init, err = parse("init", m.manifest.Init, snippetFuncs)
+	if err != nil {
+		return nil, err
+	}
+
+	m.sel, err = parse("select", m.manifest.Select, snippetFuncs)
+	if err != nil {
+		return nil, err
+	}
+
+	m.shutdown, err = parse("shutdown", m.manifest.Shutdown, snippetFuncs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Execute the snippets once, so a typo in a field is reported now instead of being dropped from the code
+	for _, tmpl := range []*template.Template{m.init, m.sel, m.shutdown} {
+		if tmpl == nil {
+			continue
+		}
+
+		err = tmpl.Execute(&bytes.Buffer{}, m.data(m.exampleNamespace()))
+		if err != nil {
+			return nil, fmt.Errorf("error executing template %s: %s", tmpl.Name(), err)
+		}
+	}
+
+	for _, f := range m.manifest.Files {
+		b, err := afero.ReadFile(fsys, filepath.Join(dir, f.Template))
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := parse(f.Template, string(b), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		pathTmpl, err := parse(f.Path, f.Path, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		m.files = append(m.files, file{path: pathTmpl, content: content})
+
+		_, err = m.filePath(pathTmpl, m.exampleNamespace())
+		if err != nil {
+			return nil, fmt.Errorf("error in %s: %s", filepath.Join(dir, ManifestFile), err)
+		}
+	}
+
+	return m, nil
+}
+
+// LoadAll loads every plugin in the subdirectories of dir, directories without a manifest are skipped
+func LoadAll(fsys afero.Fs, dir string) ([]*Module, error) {
+	entries, err := afero.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var modules []*Module
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		ok, err := afero.Exists(fsys, filepath.Join(dir, entry.Name(), ManifestFile))
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		m, err := Load(fsys, filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		modules = append(modules, m)
+	}
+
+	return modules, nil
+}
+
+// Register adds the plugin to the registry as an adapter or as a flavor of its service
+func Register(m *Module) error {
+	switch m.manifest.Kind {
+	case KindFlavor:
+		service, ok := registry.Services()[m.manifest.Service]
+		if !ok {
+			return fmt.Errorf("error registering flavor %s: service %s doesn't exist", m.GetName(), m.manifest.Service)
+		}
+
+		if service.GetFlavor(m.GetName()) != nil {
+			return fmt.Errorf("error registering flavor %s: service %s already has a flavor with that name", m.GetName(), m.manifest.Service)
+		}
+
+		registry.RegisterFlavor(m.manifest.Service, m)
+	default:
+		if _, ok := registry.Adapters()[m.GetName()]; ok {
+			return fmt.Errorf("error registering adapter %s: an adapter with that name already exists", m.GetName())
+		}
+
+		registry.RegisterAdapter(m)
+	}
+
+	return nil
+}
+
+// Kind returns whether the plugin is an adapter or a flavor
+func (m *Module) Kind() string {
+	return m.manifest.Kind
+}
+
+// GetName returns the name of the plugin
+func (m *Module) GetName() string {
+	return m.manifest.Name
+}
+
+// GetDisplayName - what will be displayed in the CLI when prompted
+func (m *Module) GetDisplayName() string {
+	return m.manifest.DisplayName
+}
+
+// GetDescription - returns the description of the flavor
+func (m *Module) GetDescription() string {
+	return m.manifest.Description
+}
+
+// ConfigYAML is the configuration of the plugin in YAML format
+func (m *Module) ConfigYAML() map[string]interface{} {
+	if len(m.manifest.Config.Fields) == 0 {
+		return nil
+	}
+
+	values := make(map[string]interface{}, len(m.manifest.Config.Fields))
+	for _, field := range m.manifest.Config.Fields {
+		values[field.Name] = field.value()
+	}
+
+	return map[string]interface{}{
+		m.manifest.configKey(): values,
+	}
+}
+
+// ConfigGo is the configuration of the plugin in Go format
+func (m *Module) ConfigGo() *j.Statement {
+	if len(m.manifest.Config.Fields) == 0 {
+		return nil
+	}
+
+	var fields []j.Code
+	for _, field := range m.manifest.Config.Fields {
+		fields = append(fields, j.Id(exported(field.Name)).Id(field.goType()).Tag(map[string]string{"mapstructure": field.Name, "json": field.Name}))
+	}
+
+	key := m.manifest.configKey()
+	return j.Id(exported(key)).Struct(fields...).Tag(map[string]string{"mapstructure": key, "json": key})
+}
+
+// AppInit is the code that will be added to the START internal/app/app.go Run() function
+func (m *Module) AppInit(ns domain.Namespace) []j.Code {
+	code := m.snippet(m.init, ns)
+	if code == nil {
+		return nil
+	}
+
+	return []j.Code{j.Line(), code, j.Line()}
+}
+
+// AppSelect - Each AppSelect branch is apart of a bigger switch statement that's in the internal/app/app.go Run() function
+func (m *Module) AppSelect(ns domain.Namespace) j.Code {
+	return m.snippet(m.sel, ns)
+}
+
+// AppShutdown is the code that will be added to the END internal/app/app.go Run() function
+func (m *Module) AppShutdown(ns domain.Namespace) []j.Code {
+	code := m.snippet(m.shutdown, ns)
+	if code == nil {
+		return nil
+	}
+
+	return []j.Code{code}
+}
+
+// Constraints - what the plugin needs from the rest of the project
+func (m *Module) Constraints() domain.Constraints {
+	return domain.Constraints{
+		Requires:  m.manifest.Requires,
+		Conflicts: m.manifest.Conflicts,
+		GoVersion: m.manifest.GoVersion,
+		Packages:  []string{m.manifest.pkg()},
+	}
+}
+
+// Service renders the files of the plugin, Go files are formatted
+func (m *Module) Service(fs afero.Fs, ns domain.Namespace, path string) error {
+	for _, f := range m.files {
+		name, err := m.filePath(f.path, ns)
+		if err != nil {
+			return &domain.GenerateError{Module: m.GetName(), File: f.path.Name(), Err: err}
+		}
+
+		buf := &bytes.Buffer{}
+		err = f.content.Execute(buf, m.data(ns))
+		if err != nil {
+			return &domain.GenerateError{Module: m.GetName(), File: name, Err: err}
+		}
+
+		b := buf.Bytes()
+		if strings.HasSuffix(name, ".go") {
+			b, err = format.Source(b)
+			if err != nil {
+				return &domain.GenerateError{Module: m.GetName(), File: name, Err: err}
+			}
+		}
+
+		filename := filepath.Join(path, filepath.FromSlash(name))
+		err = fs.MkdirAll(filepath.Dir(filename), 0755)
+		if err != nil {
+			return &domain.GenerateError{Module: m.GetName(), File: name, Err: err}
+		}
+
+		err = afero.WriteFile(fs, filename, b, 0644)
+		if err != nil {
+			return &domain.GenerateError{Module: m.GetName(), File: name, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// filePath executes the path of a file, it has to stay inside of the project
+func (m *Module) filePath(tmpl *template.Template, ns domain.Namespace) (string, error) {
+	buf := &bytes.Buffer{}
+	err := tmpl.Execute(buf, m.data(ns))
+	if err != nil {
+		return "", err
+	}
+
+	name := path.Clean(buf.String())
+	if path.IsAbs(name) || name == "." || strings.HasPrefix(name, "..") {
+		return "", fmt.Errorf("file %s must be relative to the project", buf.String())
+	}
+
+	return name, nil
+}
+
+// data is what the templates of the plugin are executed with
+func (m *Module) data(ns domain.Namespace) Data {
+	return Data{
+		Namespace: ns,
+		Config:    exported(m.manifest.configKey()),
+	}
+}
+
+// exampleNamespace is the namespace the templates are checked with when the plugin is loaded
+func (m *Module) exampleNamespace() domain.Namespace {
+	return domain.Namespace{
+		Module:  "example.com/module",
+		Name:    m.manifest.Name,
+		Package: m.manifest.pkg(),
+	}
+}
+
+// snippet executes a snippet template and turns the output into jennifer code, qual calls become qualified identifiers
+// Load already executed the snippets once, so an error here can't happen and returns no code
+func (m *Module) snippet(tmpl *template.Template, ns domain.Namespace) j.Code {
+	if tmpl == nil {
+		return nil
+	}
+
+	buf := &bytes.Buffer{}
+	err := tmpl.Execute(buf, m.data(ns))
+	if err != nil || strings.TrimSpace(buf.String()) == "" {
+		return nil
+	}
+
+	code := j.Null()
+	for i, part := range strings.Split(strings.TrimSpace(buf.String()), qualSep) {
+		if i%2 == 0 {
+			if part != "" {
+				code.Id(part)
+			}
+			continue
+		}
+
+		pkg, name, _ := strings.Cut(part, qualNameSep)
+		code.Qual(pkg, name)
+	}
+
+	return code
+}
+
+func (mf *Manifest) validate() error {
+	if mf.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	if mf.DisplayName == "" {
+		mf.DisplayName = mf.Name
+	}
+
+	switch mf.Kind {
+	case "", KindAdapter:
+		mf.Kind = KindAdapter
+		if mf.Service != "" {
+			return fmt.Errorf("service is only allowed for flavors")
+		}
+	case KindFlavor:
+		if mf.Service == "" {
+			return fmt.Errorf("service is required for flavors")
+		}
+	default:
+		return fmt.Errorf("unknown kind %s, expected %s or %s", mf.Kind, KindAdapter, KindFlavor)
+	}
+
+	for _, field := range mf.Config.Fields {
+		if field.Name == "" {
+			return fmt.Errorf("config field without a name")
+		}
+
+		switch field.Type {
+		case "", "string", "int", "bool":
+		default:
+			return fmt.Errorf("config field %s has unknown type %s, expected string, int or bool", field.Name, field.Type)
+		}
+	}
+
+	for _, file := range mf.Files {
+		if file.Path == "" || file.Template == "" {
+			return fmt.Errorf("files need a path and a template")
+		}
+	}
+
+	return nil
+}
+
+// pkg is the folder of the package the plugin generates, the namespace of the plugin can move it
+func (mf *Manifest) pkg() string {
+	if mf.Package != "" {
+		return mf.Package
+	}
+
+	return "pkg/" + mf.Name
+}
+
+func (mf *Manifest) configKey() string {
+	if mf.Config.Key != "" {
+		return mf.Config.Key
+	}
+
+	return mf.Name
+}
+
+func (f Field) goType() string {
+	if f.Type == "" {
+		return "string"
+	}
+
+	return f.Type
+}
+
+// value is the default converted to the type of the field, so ports like 6379 are still strings in config.yaml
+func (f Field) value() interface{} {
+	if f.goType() == "string" {
+		if f.Default == nil {
+			return ""
+		}
+		return fmt.Sprint(f.Default)
+	}
+
+	return f.Default
+}
+
+// Separators the qual template function wraps a qualified identifier in, they can't appear in Go source
+const (
+	qualSep     = "\x00"
+	qualNameSep = "\x01"
+)
+
+// snippetFuncs are the functions available in init, select and shutdown
+// {{qual "github.com/bradfitz/gomemcache/memcache" "New"}} is rendered as memcache.New and the import is added to app.go
+var snippetFuncs = template.FuncMap{
+	"qual": func(pkg, name string) string {
+		return qualSep + pkg + qualNameSep + name + qualSep
+	},
+}
+
+// parse parses a template, an empty text is no template. Missing keys are errors so typos don't end up in the code
+func parse(name, text string, funcs template.FuncMap) (*template.Template, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing template %s: %s", name, err)
+	}
+
+	return tmpl, nil
+}
+
+// exported turns a config key into an exported Go identifier, i.e. max_conns becomes MaxConns
+func exported(key string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(key, func(r rune) bool { return r == '_' || r == '-' || r == '.' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+
+	return b.String()
+}
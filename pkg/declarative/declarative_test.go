@@ -0,0 +1,121 @@
+package declarative
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+
+	"github.com/mahcks/gowizard/pkg/generator"
+)
+
+// TestGenerate registers the memcached plugin in testdata and generates a project with it
+func TestGenerate(t *testing.T) {
+	modules, err := LoadAll(afero.NewOsFs(), "testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(modules) != 1 || modules[0].GetName() != "memcached" {
+		t.Fatalf("expected the memcached plugin, got %d plugins", len(modules))
+	}
+
+	err = Register(modules[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Register(modules[0])
+	if err == nil {
+		t.Fatal("expected an error registering the plugin twice")
+	}
+
+	fs := afero.NewMemMapFs()
+	dir := "/project"
+	gen := generator.NewGenerator(
+		generator.WithModule("github.com/gowizard/plugin"),
+		generator.WithGoVersion("1.20"),
+		generator.WithPath(dir),
+		generator.WithAdapters("memcached", "redis"),
+		generator.WithFs(fs),
+		generator.WithDryRun(true),
+	)
+
+	err = gen.Generate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string][]string{
+		"internal/app/app.go": {
+			`memcached "github.com/gowizard/plugin/pkg/memcached"`,
+			"memcachedClient, err := memcached.New(cfg.Memcached.Host, cfg.Memcached.Port, cfg.Memcached.MaxIdleConns)",
+			"memcachedClient.Close()",
+		},
+		"config/config.go": {
+			"MaxIdleConns int    `json:\"max_idle_conns\" mapstructure:\"max_idle_conns\"`",
+		},
+		"config/config.yaml": {
+			"max_idle_conns: 2",
+			`port: "11211"`,
+		},
+		"pkg/memcached/adapter.go": {
+			"func New(host, port string, maxIdleConns int) (*Memcached, error) {",
+		},
+	}
+
+	for file, snippets := range expected {
+		b, err := afero.ReadFile(fs, filepath.Join(dir, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, snippet := range snippets {
+			if !strings.Contains(string(b), snippet) {
+				t.Errorf("%s doesn't contain %q:\n%s", file, snippet, b)
+			}
+		}
+	}
+
+	if testing.Short() {
+		return
+	}
+
+	errs, err := gen.Verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, e := range errs {
+		t.Errorf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Msg)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	cases := map[string]string{
+		"no name":       "kind: adapter\n",
+		"unknown kind":  "kind: controller\nname: x\n",
+		"flavor":        "kind: flavor\nname: x\n",
+		"field type":    "name: x\nconfig:\n  fields:\n    - name: port\n      type: float\n",
+		"unknown key":   "name: x\nshutdwn: x.Close()\n",
+		"template":      "name: x\ninit: '{{.Modul}}'\n",
+		"absolute file": "name: x\nfiles:\n  - path: /etc/passwd\n    template: x.tmpl\n",
+	}
+
+	for name, manifest := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			err := afero.WriteFile(fs, "/plugin/"+ManifestFile, []byte(manifest), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = Load(fs, "/plugin")
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
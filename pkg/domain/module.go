@@ -1,7 +1,10 @@
 package domain
 
 import (
+	"fmt"
+
 	j "github.com/dave/jennifer/jen"
+	"github.com/spf13/afero"
 )
 
 type ModuleI interface {
@@ -15,13 +18,15 @@ type ModuleI interface {
 	// ConfigGo is the configuration of the module in Go format
 	ConfigGo() *j.Statement
 	// AppInit is the code that will be added to the START internal/app/app.go Run() function
-	AppInit(module string) []j.Code
+	AppInit(ns Namespace) []j.Code
 	// AppSelect - Each AppSelect branch is apart of a bigger switch statement that's in the internal/app/app.go Run() function
-	AppSelect(module string) j.Code
+	AppSelect(ns Namespace) j.Code
 	// AppInit is the code that will be added to the END internal/app/app.go Run() function
-	AppShutdown(module string) []j.Code
-	// Service is the code that will be added to its own `pkg` folder
-	Service(module, path string) *j.File
+	AppShutdown(ns Namespace) []j.Code
+	// Service is the code that will be added to the package of its namespace, a failure is returned as a *GenerateError
+	Service(fs afero.Fs, ns Namespace, path string) error
+	// Constraints - what the module needs from the rest of the project, enforced by the generator and the wizard
+	Constraints() Constraints
 }
 
 type ServiceI interface {
@@ -29,7 +34,7 @@ type ServiceI interface {
 	GetName() string
 	// GetDisplayName - what will be displayed in the CLI when prompted
 	GetDisplayName() string
-	// GetFlavors - returns the flavors that are available for this service
+	// GetFlavors - returns the flavors that are available for this service, registry.RegisterFlavor adds to this map
 	GetFlavors() map[string]FlavorI
 	// GetFlavor - returns flavor by name
 	GetFlavor(flavor string) FlavorI
@@ -48,22 +53,66 @@ type FlavorI interface {
 	// ConfigGo is the configuration of the module in Go format
 	ConfigGo() *j.Statement
 	// AppInit is the code that will be added to the START internal/app/app.go Run() function
-	AppInit(module string) []j.Code
+	AppInit(ns Namespace) []j.Code
 	// AppSelect - Each AppSelect branch is apart of a bigger switch statement that's in the internal/app/app.go Run() function
-	AppSelect(module string) j.Code
+	AppSelect(ns Namespace) j.Code
 	// AppInit is the code that will be added to the END internal/app/app.go Run() function
-	AppShutdown(module string) []j.Code
-	// Service is the code that will be added to its own `pkg` folder
-	Service(module, path string) *j.File
+	AppShutdown(ns Namespace) []j.Code
+	// Service is the code that will be added to the package of its namespace, a failure is returned as a *GenerateError
+	Service(fs afero.Fs, ns Namespace, path string) error
+	// Constraints - what the module needs from the rest of the project, enforced by the generator and the wizard
+	Constraints() Constraints
+}
+
+// Constraints describes which adapters and Go version a module needs and what it can't be combined with
+type Constraints struct {
+	Requires  []string // Adapters that have to be enabled as well, i.e. redis for a job queue
+	Conflicts []string // Adapters that can't be enabled at the same time
+	GoVersion string   // Minimum Go version of the generated module, i.e. 1.21
+	Packages  []string // Folders the module generates relative to the project, the first one is the package of its namespace
+}
+
+// CommandsI is implemented by adapters and flavors that run a tool in the project after `go mod tidy`, i.e. a code generator
+// The commands aren't run in a dry run
+type CommandsI interface {
+	// Commands returns the commands to run in the project folder
+	Commands(ns Namespace) []string
+}
+
+// MigrationsI is implemented by the adapters of SQL databases that the generator can scaffold golang-migrate migrations for
+type MigrationsI interface {
+	// MigrateDriver returns the golang-migrate database driver, i.e. github.com/golang-migrate/migrate/v4/database/postgres
+	MigrateDriver() string
+	// MigrateURL returns the database URL the migrations are applied with in app.go, i.e. cfg.Postgres.URL
+	MigrateURL() *j.Statement
+	// InitialMigration returns the up and down SQL of the first migration
+	InitialMigration() (up, down string)
+}
+
+// GenerateError is returned when an adapter or flavor fails to generate one of its files
+type GenerateError struct {
+	Module string // Name of the adapter or flavor
+	File   string // Path of the file or folder relative to the project
+	Err    error
+}
+
+func (e *GenerateError) Error() string {
+	return fmt.Sprintf("%s: error generating %s: %s", e.Module, e.File, e.Err)
+}
+
+func (e *GenerateError) Unwrap() error {
+	return e.Err
 }
 
 type Settings struct {
-	Path          string            // Path to the module
-	Module        string            // Module name
-	ModuleVersion string            // Go module version
-	Adapters      []string          // Enabled adapters
-	Services      map[string]string // Enabled services, key is the service name, value is the flavor name
-	Controllers   []string          // Enabled controllers
+	Path          string            `json:"path"`                  // Path to the module
+	Module        string            `json:"module"`                // Module name
+	ModuleVersion string            `json:"module_version"`        // Go module version
+	Adapters      []string          `json:"adapters,omitempty"`    // Enabled adapters
+	Services      map[string]string `json:"services,omitempty"`    // Enabled services, key is the service name, value is the flavor name
+	Controllers   []string          `json:"controllers,omitempty"` // Enabled controllers
+	Logger        string            `json:"logger,omitempty"`      // Logger of the project, i.e. slog, the modules print with fmt without one
+	Migrations    string            `json:"migrations,omitempty"`  // Adapter of the SQL database migrations are scaffolded for, i.e. postgres
 }
 
 // IsAdapterChecked checks if the adapter is enabled
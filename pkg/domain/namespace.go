@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"path"
+	"strings"
+	"unicode"
+
+	j "github.com/dave/jennifer/jen"
+)
+
+// Namespace keeps the identifiers and the package of a module apart from the other modules of the project
+// The generator gives every enabled adapter and flavor its own namespace
+type Namespace struct {
+	Module  string // Module name, i.e. github.com/user/module
+	Name    string // Prefix of the identifiers the module declares in app.go, i.e. redis or rest
+	Package string // Folder of the package the module generates relative to the project, i.e. pkg/httpserver
+	Logger  string // Logger of the project, i.e. slog, empty when the project has none
+}
+
+// Id returns an identifier that is unique to the module, i.e. Id("server") is restServer for the rest service
+func (ns Namespace) Id(name string) string {
+	return lowerFirst(camel(ns.Name)) + upperFirst(name)
+}
+
+// Path returns the import path of the package of the module, i.e. github.com/user/module/pkg/httpserver
+func (ns Namespace) Path() string {
+	return ns.Module + "/" + ns.Package
+}
+
+// PackageName returns the name of the package of the module, the last element of its folder
+func (ns Namespace) PackageName() string {
+	return path.Base(ns.Package)
+}
+
+// Log returns a call that logs msg in the Run function of app.go, level is Debug, Info, Warn or Error
+// args are key-value pairs, i.e. Log("Error", "error connecting to redis", j.Lit("error"), j.Err())
+// Without a logger msg and the values are printed with fmt.Println, the keys are left out
+func (ns Namespace) Log(level, msg string, args ...j.Code) *j.Statement {
+	if ns.Logger != "" {
+		return j.Id("l").Dot(level).Call(append([]j.Code{j.Lit(msg)}, args...)...)
+	}
+
+	values := []j.Code{j.Lit(msg)}
+	for i := 1; i < len(args); i += 2 {
+		values = append(values, args[i])
+	}
+
+	return j.Qual("fmt", "Println").Call(values...)
+}
+
+// camel joins the words of a name, i.e. my-cache becomes myCache
+func camel(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i := 1; i < len(words); i++ {
+		words[i] = upperFirst(words[i])
+	}
+
+	return strings.Join(words, "")
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return strings.ToLower(s[:1]) + s[1:]
+}
@@ -1,5 +1,7 @@
 package domain
 
+import "github.com/spf13/afero"
+
 type TemplateI interface {
 	// GetName returns the name of the template
 	GetName() string
@@ -7,5 +9,5 @@ type TemplateI interface {
 	GetShortDescription() string
 
 	// Setup is the code to run when the template is selected
-	Setup(path string) error
+	Setup(fs afero.Fs, path string) error
 }
@@ -0,0 +1,296 @@
+package dryrun
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mahcks/gowizard/pkg/manifest"
+	"github.com/spf13/afero"
+)
+
+// Fs is a filesystem that reads from disk but keeps every write in memory
+type Fs struct {
+	afero.Fs
+	layer afero.Fs // in-memory layer that holds everything that was written
+}
+
+// NewFs creates a filesystem that never writes to disk
+func NewFs() *Fs {
+	layer := afero.NewMemMapFs()
+
+	return &Fs{
+		Fs:    afero.NewCopyOnWriteFs(afero.NewReadOnlyFs(afero.NewOsFs()), layer),
+		layer: layer,
+	}
+}
+
+// file is a file that was written during the dry run
+type file struct {
+	path    string // slash separated path relative to the root
+	content []byte
+	disk    []byte // content of the file on disk, nil if it doesn't exist yet
+	exists  bool
+}
+
+// Print writes the tree of every file written below root, followed by a diff of each file against what's on disk
+func (f *Fs) Print(w io.Writer, root string) error {
+	files, err := f.files(root)
+	if err != nil {
+		return err
+	}
+
+	if len(files) == 0 {
+		fmt.Fprintln(w, "No files would be written")
+		return nil
+	}
+
+	fmt.Fprintln(w, filepath.Base(root))
+	printTree(w, files)
+
+	for _, file := range files {
+		fmt.Fprintln(w)
+		if file.exists {
+			fmt.Fprintf(w, "--- a/%s\n+++ b/%s\n", file.path, file.path)
+		} else {
+			fmt.Fprintf(w, "--- /dev/null\n+++ b/%s\n", file.path)
+		}
+		fmt.Fprint(w, unifiedDiff(file.disk, file.content))
+	}
+
+	return nil
+}
+
+// files returns every file below root that was written to the in-memory layer and changed from what's on disk
+func (f *Fs) files(root string) ([]file, error) {
+	var files []file
+
+	err := afero.Walk(f.layer, root, func(path string, info os.FileInfo, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && path == root {
+			return filepath.SkipDir
+		}
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			// The bases are copies of the generated files, showing them again is just noise
+			if filepath.ToSlash(rel) == manifest.BaseDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		content, err := afero.ReadFile(f.layer, path)
+		if err != nil {
+			return err
+		}
+
+		disk, err := os.ReadFile(path)
+		exists := err == nil
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+
+		if exists && string(disk) == string(content) {
+			return nil
+		}
+
+		files = append(files, file{path: filepath.ToSlash(rel), content: content, disk: disk, exists: exists})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].path < files[j].path
+	})
+
+	return files, nil
+}
+
+// node is a folder or file in the printed tree
+type node struct {
+	name     string
+	note     string
+	children map[string]*node
+}
+
+// printTree prints the files in the same style as the tree in the README
+func printTree(w io.Writer, files []file) {
+	root := &node{children: map[string]*node{}}
+	for _, file := range files {
+		current := root
+		parts := strings.Split(file.path, "/")
+		for i, part := range parts {
+			child, ok := current.children[part]
+			if !ok {
+				child = &node{name: part, children: map[string]*node{}}
+				current.children[part] = child
+			}
+
+			if i == len(parts)-1 {
+				child.note = "new"
+				if file.exists {
+					child.note = "modified"
+				}
+			}
+			current = child
+		}
+	}
+
+	printNode(w, root, " ")
+}
+
+// printNode prints the children of a node, folders first
+func printNode(w io.Writer, n *node, prefix string) {
+	children := make([]*node, 0, len(n.children))
+	for _, child := range n.children {
+		children = append(children, child)
+	}
+
+	sort.Slice(children, func(i, j int) bool {
+		iDir, jDir := len(children[i].children) != 0, len(children[j].children) != 0
+		if iDir != jDir {
+			return iDir
+		}
+		return children[i].name < children[j].name
+	})
+
+	for i, child := range children {
+		branch, indent := "┣ ", "┃ "
+		if i == len(children)-1 {
+			branch, indent = "┗ ", "  "
+		}
+
+		if child.note != "" {
+			fmt.Fprintf(w, "%s%s%s (%s)\n", prefix, branch, child.name, child.note)
+		} else {
+			fmt.Fprintf(w, "%s%s%s\n", prefix, branch, child.name)
+		}
+		printNode(w, child, prefix+indent)
+	}
+}
+
+// contextLines is the number of unchanged lines shown around every change
+const contextLines = 3
+
+// unifiedDiff returns the hunks of a line based unified diff between two versions of a file
+func unifiedDiff(a, b []byte) string {
+	aLines, bLines := splitLines(a), splitLines(b)
+
+	// Longest common subsequence of the lines, lcs[i][j] is the length for aLines[i:] and bLines[j:]
+	lcs := make([][]int, len(aLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bLines)+1)
+	}
+	for i := len(aLines) - 1; i >= 0; i-- {
+		for j := len(bLines) - 1; j >= 0; j-- {
+			if aLines[i] == bLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	// Walk the table to get every line as kept, removed or added
+	type line struct {
+		op   byte
+		text string
+		a, b int // line numbers in both versions before this line
+	}
+
+	var lines []line
+	i, j := 0, 0
+	for i < len(aLines) || j < len(bLines) {
+		switch {
+		case i < len(aLines) && j < len(bLines) && aLines[i] == bLines[j]:
+			lines = append(lines, line{' ', aLines[i], i, j})
+			i++
+			j++
+		case i < len(aLines) && (j == len(bLines) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', aLines[i], i, j})
+			i++
+		default:
+			lines = append(lines, line{'+', bLines[j], i, j})
+			j++
+		}
+	}
+
+	// Group changes that are close to each other into hunks
+	var sb strings.Builder
+	for start := 0; start < len(lines); {
+		if lines[start].op == ' ' {
+			start++
+			continue
+		}
+
+		from := start - contextLines
+		if from < 0 {
+			from = 0
+		}
+
+		to, unchanged := start, 0
+		for to < len(lines) && unchanged <= 2*contextLines {
+			if lines[to].op == ' ' {
+				unchanged++
+			} else {
+				unchanged = 0
+			}
+			to++
+		}
+		if unchanged > contextLines {
+			to -= unchanged - contextLines
+		}
+
+		aCount, bCount := 0, 0
+		for _, l := range lines[from:to] {
+			if l.op != '+' {
+				aCount++
+			}
+			if l.op != '-' {
+				bCount++
+			}
+		}
+
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(lines[from].a, aCount), hunkRange(lines[from].b, bCount))
+		for _, l := range lines[from:to] {
+			fmt.Fprintf(&sb, "%c%s\n", l.op, l.text)
+		}
+
+		start = to
+	}
+
+	return sb.String()
+}
+
+// hunkRange formats the start and length of a hunk, empty ranges start at the line before them
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// splitLines splits content into lines without their line endings
+func splitLines(b []byte) []string {
+	if len(b) == 0 {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+}
@@ -4,80 +4,217 @@ import (
 	"os"
 
 	j "github.com/dave/jennifer/jen"
+	"github.com/spf13/afero"
 
 	"github.com/mahcks/gowizard/pkg/domain"
+	"github.com/mahcks/gowizard/pkg/flavors/httpserver"
 	"github.com/mahcks/gowizard/pkg/utils"
 )
 
-type Gin struct {
+type GQLGen struct {
 	name        string // name of the flavor
 	displayName string // name of the adapter that will be displayed in the CLI
 	description string // description of the flavor
 }
 
 // GetName returns the name of the flavor
-func (flv *Gin) GetName() string {
+func (flv *GQLGen) GetName() string {
 	return flv.name
 }
 
 // GetDisplayName - what will be displayed in the CLI when prompted
-func (flv *Gin) GetDisplayName() string {
+func (flv *GQLGen) GetDisplayName() string {
 	return flv.displayName
 }
 
 // GetDescription - returns the description of the flavor
-func (flv *Gin) GetDescription() string {
+func (flv *GQLGen) GetDescription() string {
 	return flv.description
 }
 
 func NewGQLGenFlavor() domain.FlavorI {
-	return &Gin{
+	return &GQLGen{
 		name:        "gqlgen",
-		displayName: "gqlgen",
+		displayName: "github.com/99designs/gqlgen",
+		description: "gqlgen is a Go library for building GraphQL servers without any fuss. The server is generated from a schema.",
 	}
 }
 
+// gqlgenConfig is the gqlgen.yml of the package, the paths are relative to it
+const gqlgenConfig = `# Where are all the schema files located? globs are supported eg  src/**/*.graphqls
+schema:
+  - graph/*.graphqls
+
+# Where should the generated server code go?
+exec:
+  filename: graph/generated.go
+  package: graph
+
+# Where should any generated models go?
+model:
+  filename: graph/model/models_gen.go
+  package: model
+
+# Where should the resolver implementations go?
+resolver:
+  layout: follow-schema
+  dir: graph
+  package: graph
+  filename_template: "{name}.resolvers.go"
+`
+
+// starterSchema is the schema the resolver stubs are written for
+const starterSchema = `type Query {
+  hello(name: String): String!
+}
+`
+
 // ConfigYAML is the configuration of the adapter in YAML format
-func (flv *Gin) ConfigYAML() map[string]interface{} {
-	return nil
+func (flv *GQLGen) ConfigYAML() map[string]interface{} {
+	return httpserver.ConfigYAML("gql", ":8081", httpserver.NetHTTP)
 }
 
 // ConfigGo is the configuration of the adapter in Go format
-func (flv *Gin) ConfigGo() *j.Statement {
-	return nil
+func (flv *GQLGen) ConfigGo() *j.Statement {
+	return httpserver.ConfigGo("Gql", "gql", httpserver.NetHTTP)
 }
 
 // AppInit is the code that will be added to the START internal/app/app.go Run() function
-func (flv *Gin) AppInit(module string) []j.Code {
-	return nil
+func (flv *GQLGen) AppInit(ns domain.Namespace) []j.Code {
+	return []j.Code{
+		j.Id(ns.Id("handler")).Op(":=").Qual(ns.Path(), "NewHandler").Call(),
+		j.Line(),
+		j.Id(ns.Id("server")).Op(":=").Add(httpserver.NewCall(ns, "cfg.Gql", httpserver.NetHTTP, j.Id(ns.Id("handler")))),
+	}
 }
 
-func (flv *Gin) AppSelect(module string) j.Code {
-	return nil
+// AppSelect is the branch that will be added to the select of the internal/app/app.go Run() function
+func (flv *GQLGen) AppSelect(ns domain.Namespace) j.Code {
+	return httpserver.AppSelect(ns)
 }
 
 // AppShutdown is the code that will be added to the END internal/app/app.go Run() function
-func (flv *Gin) AppShutdown(module string) []j.Code {
-	return nil
+func (flv *GQLGen) AppShutdown(ns domain.Namespace) []j.Code {
+	return httpserver.AppShutdown(ns)
 }
 
-// Service is the code that will be added to its own `pkg` folder
-func (flv *Gin) Service(module, path string) *j.File {
-	f := j.NewFilePathName(module+"/pkg/gql", "gql")
+// Constraints - what the flavor needs from the rest of the project
+func (flv *GQLGen) Constraints() domain.Constraints {
+	return domain.Constraints{
+		Packages: []string{"pkg/gqlserver"},
+	}
+}
 
-	// Before saving the file, create the directories if they don't exist
-	outputPath := path + "/pkg/gqlserver"
-	err := os.MkdirAll(outputPath, os.ModePerm)
-	if err != nil {
-		utils.PrintError("error creating directories: %s", err)
-		return nil
+// Commands - gqlgen generates the executable schema and the models from the schema, go mod tidy adds their imports
+func (flv *GQLGen) Commands(ns domain.Namespace) []string {
+	return []string{
+		"go generate ./" + ns.Package + "/...",
+		"go mod tidy",
 	}
+}
+
+// Service is the code that will be added to the package of its namespace
+// The graph package is completed by gqlgen, until `go generate` runs it lacks NewExecutableSchema
+func (flv *GQLGen) Service(fs afero.Fs, ns domain.Namespace, path string) error {
+	graphPath := ns.Path() + "/graph"
 
-	err = f.Save(outputPath + "/server.go")
+	// Before saving the files, create the directories if they don't exist
+	outputPath := path + "/" + ns.Package
+	err := fs.MkdirAll(outputPath+"/graph", os.ModePerm)
 	if err != nil {
-		utils.PrintError("error saving file: %s", err)
-		return nil
+		return &domain.GenerateError{Module: flv.name, File: ns.Package + "/graph", Err: err}
+	}
+
+	files := map[string]string{
+		"gqlgen.yml":            gqlgenConfig,
+		"graph/schema.graphqls": starterSchema,
+	}
+
+	for name, content := range files {
+		err = afero.WriteFile(fs, outputPath+"/"+name, []byte(content), 0644)
+		if err != nil {
+			return &domain.GenerateError{Module: flv.name, File: ns.Package + "/" + name, Err: err}
+		}
 	}
 
-	return f
+	// graph/resolver.go
+	resolver := j.NewFilePathName(graphPath, "graph")
+	resolver.Comment("This file will not be regenerated automatically.")
+	resolver.Comment("")
+	resolver.Comment("It serves as dependency injection for your app, add any dependencies you require here.")
+	resolver.Line()
+	resolver.Type().Id("Resolver").Struct()
+
+	// graph/schema.resolvers.go, gqlgen keeps the bodies of the resolvers when it regenerates the file
+	resolvers := j.NewFilePathName(graphPath, "graph")
+	resolvers.Comment("Hello is the resolver for the hello field.")
+	resolvers.Func().Params(j.Id("r").Add(utils.Jptr).Id("queryResolver")).Id("Hello").Params(
+		j.Id("ctx").Qual("context", "Context"),
+		j.Id("name").Add(utils.Jptr).String(),
+	).Params(j.String(), j.Error()).Block(
+		j.If(j.Id("name").Op("==").Nil()).Block(
+			j.Return(j.Lit("Hello, world!"), j.Nil()),
+		),
+		j.Line(),
+		j.Return(j.Lit("Hello, ").Op("+").Op("*").Id("name").Op("+").Lit("!"), j.Nil()),
+	)
+
+	resolvers.Line()
+	resolvers.Comment("Query returns QueryResolver implementation.")
+	resolvers.Func().Params(j.Id("r").Add(utils.Jptr).Id("Resolver")).Id("Query").Params().Id("QueryResolver").Block(
+		j.Return(j.Op("&").Id("queryResolver").Values(j.Id("r"))),
+	)
+
+	resolvers.Line()
+	resolvers.Type().Id("queryResolver").Struct(j.Add(utils.Jptr).Id("Resolver"))
+
+	// handler.go serves the schema and the playground, it's where gqlgen is run from
+	handler := j.NewFilePathName(ns.Path(), ns.PackageName())
+	handler.Comment("//go:generate go run github.com/99designs/gqlgen generate")
+	handler.Line()
+	handler.Comment("NewHandler - Serves the GraphQL API on /query and the playground on /")
+	handler.Func().Id("NewHandler").Params().Qual("net/http", "Handler").Block(
+		j.Id("srv").Op(":=").Qual("github.com/99designs/gqlgen/graphql/handler", "New").Call(
+			j.Qual(graphPath, "NewExecutableSchema").Call(
+				j.Qual(graphPath, "Config").Values(j.Dict{
+					j.Id("Resolvers"): j.Op("&").Qual(graphPath, "Resolver").Values(),
+				}),
+			),
+		),
+		j.Id("srv").Dot("AddTransport").Call(j.Qual("github.com/99designs/gqlgen/graphql/handler/transport", "Options").Values()),
+		j.Id("srv").Dot("AddTransport").Call(j.Qual("github.com/99designs/gqlgen/graphql/handler/transport", "GET").Values()),
+		j.Id("srv").Dot("AddTransport").Call(j.Qual("github.com/99designs/gqlgen/graphql/handler/transport", "POST").Values()),
+		j.Id("srv").Dot("Use").Call(j.Qual("github.com/99designs/gqlgen/graphql/handler/extension", "Introspection").Values()),
+		j.Line(),
+		j.Id("mux").Op(":=").Qual("net/http", "NewServeMux").Call(),
+		j.Id("mux").Dot("Handle").Call(j.Lit("/"), j.Qual("github.com/99designs/gqlgen/graphql/playground", "Handler").Call(j.Lit("GraphQL playground"), j.Lit("/query"))),
+		j.Id("mux").Dot("Handle").Call(j.Lit("/query"), j.Id("srv")),
+		j.Line(),
+		j.Return(j.Id("mux")),
+	)
+
+	// tools.go keeps gqlgen in go.mod, so `go run` uses the version of the module
+	tools := j.NewFilePathName(ns.Path(), ns.PackageName())
+	tools.HeaderComment("//go:build tools")
+	tools.Anon("github.com/99designs/gqlgen")
+
+	generated := []struct {
+		file *j.File
+		name string
+	}{
+		{resolver, "graph/resolver.go"},
+		{resolvers, "graph/schema.resolvers.go"},
+		{handler, "handler.go"},
+		{tools, "tools.go"},
+		{httpserver.New(ns), "server.go"},
+	}
+
+	for _, g := range generated {
+		err = utils.SaveFile(fs, g.file, outputPath+"/"+g.name)
+		if err != nil {
+			return &domain.GenerateError{Module: flv.name, File: ns.Package + "/" + g.name, Err: err}
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,269 @@
+package services
+
+import (
+	"fmt"
+	"os"
+
+	j "github.com/dave/jennifer/jen"
+	"github.com/spf13/afero"
+
+	"github.com/mahcks/gowizard/pkg/domain"
+	"github.com/mahcks/gowizard/pkg/flavors/httpserver"
+	"github.com/mahcks/gowizard/pkg/utils"
+)
+
+type GRPCGo struct {
+	name        string // name of the flavor
+	displayName string // name of the adapter that will be displayed in the CLI
+	description string // description of the flavor
+}
+
+// GetName returns the name of the flavor
+func (flv *GRPCGo) GetName() string {
+	return flv.name
+}
+
+// GetDisplayName - what will be displayed in the CLI when prompted
+func (flv *GRPCGo) GetDisplayName() string {
+	return flv.displayName
+}
+
+// GetDescription - returns the description of the flavor
+func (flv *GRPCGo) GetDescription() string {
+	return flv.description
+}
+
+func NewGRPCGoFlavor() domain.FlavorI {
+	return &GRPCGo{
+		name:        "grpc-go",
+		displayName: "github.com/grpc/grpc-go",
+		description: "The Go implementation of gRPC, a high performance, open source, general RPC framework that puts mobile and HTTP/2 first.",
+	}
+}
+
+// starterProto is the proto file of the starter service, %s is its go_package
+const starterProto = `syntax = "proto3";
+
+package greeter.v1;
+
+option go_package = "%s";
+
+// GreeterService is a starter service, replace it with the services of the project
+service GreeterService {
+  rpc SayHello(SayHelloRequest) returns (SayHelloResponse);
+}
+
+message SayHelloRequest {
+  string name = 1;
+}
+
+message SayHelloResponse {
+  string message = 1;
+}
+`
+
+// bufConfig makes the proto folder the root of the buf module
+const bufConfig = `version: v1
+`
+
+// bufGenConfig generates the messages and the services next to the proto files with the local protoc-gen-go and protoc-gen-go-grpc
+const bufGenConfig = `version: v1
+plugins:
+  - plugin: go
+    out: proto
+    opt: paths=source_relative
+  - plugin: go-grpc
+    out: proto
+    opt: paths=source_relative
+`
+
+// grpcFields are the fields of the Service the options set, the server has no read and write timeouts
+var grpcFields = httpserver.Fields{
+	Addr:            "s.addr",
+	ShutdownTimeout: "s.shutdownTimeout",
+}
+
+// ConfigYAML is the configuration of the adapter in YAML format
+func (flv *GRPCGo) ConfigYAML() map[string]interface{} {
+	return httpserver.ConfigYAML("grpc", ":9090", grpcFields)
+}
+
+// ConfigGo is the configuration of the adapter in Go format
+func (flv *GRPCGo) ConfigGo() *j.Statement {
+	return httpserver.ConfigGo("Grpc", "grpc", grpcFields)
+}
+
+// AppInit is the code that will be added to the START internal/app/app.go Run() function
+func (flv *GRPCGo) AppInit(ns domain.Namespace) []j.Code {
+	return []j.Code{
+		j.Id(ns.Id("server")).Op(":=").Add(httpserver.NewCall(ns, "cfg.Grpc", grpcFields)),
+	}
+}
+
+// AppSelect is the branch that will be added to the select of the internal/app/app.go Run() function
+func (flv *GRPCGo) AppSelect(ns domain.Namespace) j.Code {
+	return httpserver.AppSelect(ns)
+}
+
+// AppShutdown is the code that will be added to the END internal/app/app.go Run() function
+func (flv *GRPCGo) AppShutdown(ns domain.Namespace) []j.Code {
+	return httpserver.AppShutdown(ns)
+}
+
+// Constraints - what the flavor needs from the rest of the project
+func (flv *GRPCGo) Constraints() domain.Constraints {
+	return domain.Constraints{
+		Packages: []string{"pkg/grpcserver"},
+	}
+}
+
+// Service is the code that will be added to the package of its namespace
+// The code generated from the proto file isn't used by the server, so the project compiles before buf or protoc ran
+func (flv *GRPCGo) Service(fs afero.Fs, ns domain.Namespace, path string) error {
+	// Before saving the files, create the directories if they don't exist
+	outputPath := path + "/" + ns.Package
+	err := fs.MkdirAll(outputPath+"/proto/greeter/v1", os.ModePerm)
+	if err != nil {
+		return &domain.GenerateError{Module: flv.name, File: ns.Package + "/proto/greeter/v1", Err: err}
+	}
+
+	files := map[string]string{
+		"buf.gen.yaml":                   bufGenConfig,
+		"proto/buf.yaml":                 bufConfig,
+		"proto/greeter/v1/greeter.proto": fmt.Sprintf(starterProto, ns.Path()+"/proto/greeter/v1;greeterv1"),
+	}
+
+	for name, content := range files {
+		err = afero.WriteFile(fs, outputPath+"/"+name, []byte(content), 0644)
+		if err != nil {
+			return &domain.GenerateError{Module: flv.name, File: ns.Package + "/" + name, Err: err}
+		}
+	}
+
+	// register.go is where the services of the project are added, it's also where the code is generated from
+	register := j.NewFilePathName(ns.Path(), ns.PackageName())
+	register.Comment("//go:generate buf generate proto")
+	register.Line()
+	register.Comment("Without buf, the code can be generated with protoc from this folder instead:")
+	register.Comment("protoc -I proto --go_out=proto --go_opt=paths=source_relative --go-grpc_out=proto --go-grpc_opt=paths=source_relative proto/greeter/v1/greeter.proto")
+	register.Line()
+	register.Comment("Register - Adds the services of the project to the server before it starts")
+	register.Comment("i.e. greeterv1.RegisterGreeterServiceServer(server, &greeterServer{}) once the code is generated")
+	register.Func().Id("Register").Params(j.Id("server").Add(utils.Jptr).Qual("google.golang.org/grpc", "Server")).Block()
+
+	// server.go
+	f := j.NewFilePathName(ns.Path(), ns.PackageName())
+
+	// Service struct
+	sStruct := j.Type().Id("Service").Struct(
+		j.Id("server").Add(utils.Jptr).Qual("google.golang.org/grpc", "Server"),
+		j.Id("health").Add(utils.Jptr).Qual("google.golang.org/grpc/health", "Server"),
+		j.Id("addr").String(),
+		j.Id("notify").Chan().Error(),
+		j.Id("shutdownTimeout").Qual("time", "Duration"),
+	)
+
+	f.Add(sStruct)
+
+	f.Var().Id("defaultAddr").Op("=").Lit(":9090")
+	f.Var().Id("defaultShutdownTimeout").Op("=").Qual("time", "Second").Op("*").Lit(5)
+
+	f.Line()
+
+	httpserver.AddOptions(f, grpcFields)
+
+	// New service
+	f.Comment("New - Starts a gRPC server with the services of Register, the health service and reflection")
+	f.Func().Id("New").Params(j.Id("opts").Op("...").Id("Option")).Add(utils.Jptr).Id("Service").Block(
+		j.Id("grpcServer").Op(":=").Qual("google.golang.org/grpc", "NewServer").Call(),
+		j.Id("Register").Call(j.Id("grpcServer")),
+		j.Line(),
+		j.Comment("Every service is reported as serving until the server shuts down"),
+		j.Id("healthServer").Op(":=").Qual("google.golang.org/grpc/health", "NewServer").Call(),
+		j.Qual("google.golang.org/grpc/health/grpc_health_v1", "RegisterHealthServer").Call(j.Id("grpcServer"), j.Id("healthServer")),
+		j.Qual("google.golang.org/grpc/reflection", "Register").Call(j.Id("grpcServer")),
+		j.Line(),
+		j.Id("s").Op(":=").Add(utils.Rptr).Id("Service").Values(
+			j.Dict{
+				j.Id("server"):          j.Id("grpcServer"),
+				j.Id("health"):          j.Id("healthServer"),
+				j.Id("addr"):            j.Id("defaultAddr"),
+				j.Id("notify"):          j.Make(j.Chan().Error().Op(",").Lit(1)),
+				j.Id("shutdownTimeout"): j.Id("defaultShutdownTimeout"),
+			},
+		),
+		j.Line(),
+		j.For(j.List(j.Id("_"), j.Id("opt")).Op(":=").Range().Id("opts")).Block(
+			j.Id("opt").Call(j.Id("s")),
+		),
+		j.Line(),
+		j.Id("s").Dot("start").Call(),
+		j.Line(),
+		j.Return(j.Id("s")),
+	)
+
+	f.Line()
+
+	// start()
+	f.Func().Params(j.Id("s").Add(utils.Jptr).Id("Service")).Id("start").Params().Block(
+		j.Id("go").Func().Params().Block(
+			j.Defer().Id("close").Call(j.Id("s").Dot("notify")),
+			j.Line(),
+			j.List(j.Id("listener"), j.Err()).Op(":=").Qual("net", "Listen").Call(j.Lit("tcp"), j.Id("s").Dot("addr")),
+			j.If(j.Err().Op("!=").Nil()).Block(
+				j.Id("s").Dot("notify").Op("<-").Err(),
+				j.Return(),
+			),
+			j.Line(),
+			j.Id("s").Dot("notify").Op("<-").Id("s").Dot("server").Dot("Serve").Call(j.Id("listener")),
+		).Call(),
+	)
+
+	f.Line()
+
+	// Notify()
+	f.Func().Params(j.Id("s").Add(utils.Jptr).Id("Service")).Id("Notify").Params().Op("<-").Chan().Error().Block(
+		j.Return(j.Id("s").Dot("notify")),
+	)
+
+	f.Line()
+
+	// Shutdown()
+	f.Comment("Shutdown - Stops accepting connections and waits for the pending RPCs, they are cancelled after the shutdown timeout")
+	f.Func().Params(j.Id("s").Add(utils.Jptr).Id("Service")).Id("Shutdown").Params().Error().Block(
+		j.Id("s").Dot("health").Dot("Shutdown").Call(),
+		j.Line(),
+		j.Id("stopped").Op(":=").Make(j.Chan().Struct()),
+		j.Go().Func().Params().Block(
+			j.Id("s").Dot("server").Dot("GracefulStop").Call(),
+			j.Id("close").Call(j.Id("stopped")),
+		).Call(),
+		j.Line(),
+		j.Select().Block(
+			j.Case(j.Op("<-").Id("stopped")).Block(
+				j.Return(j.Nil()),
+			),
+			j.Case(j.Op("<-").Qual("time", "After").Call(j.Id("s").Dot("shutdownTimeout"))).Block(
+				j.Id("s").Dot("server").Dot("Stop").Call(),
+				j.Return(j.Qual("errors", "New").Call(j.Lit("graceful stop timed out, pending RPCs were cancelled"))),
+			),
+		),
+	)
+
+	generated := []struct {
+		file *j.File
+		name string
+	}{
+		{register, "register.go"},
+		{f, "server.go"},
+	}
+
+	for _, g := range generated {
+		err = utils.SaveFile(fs, g.file, outputPath+"/"+g.name)
+		if err != nil {
+			return &domain.GenerateError{Module: flv.name, File: ns.Package + "/" + g.name, Err: err}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+// Package httpserver generates the pkg/httpserver package that the flavors with an http.Handler have in common
+package httpserver
+
+import (
+	"os"
+
+	j "github.com/dave/jennifer/jen"
+	"github.com/spf13/afero"
+
+	"github.com/mahcks/gowizard/pkg/domain"
+	"github.com/mahcks/gowizard/pkg/utils"
+)
+
+// New - The package of a namespace with a Service around a net/http server, for flavors that have an http.Handler
+// The address, timeouts and TLS files are options of New, see AddOptions
+func New(ns domain.Namespace) *j.File {
+	f := j.NewFilePathName(ns.Path(), ns.PackageName())
+
+	// Service struct
+	sStruct := j.Type().Id("Service").Struct(
+		j.Id("server").Add(utils.Jptr).Qual("net/http", "Server"),
+		j.Id("notify").Chan().Error(),
+		j.Id("shutdownTimeout").Qual("time", "Duration"),
+		j.Id("certFile").String(),
+		j.Id("keyFile").String(),
+	)
+
+	f.Add(sStruct)
+
+	f.Var().Id("defaultReadTimeout").Op("=").Qual("time", "Second").Op("*").Lit(5)
+	f.Var().Id("defaultWriteTimeout").Op("=").Qual("time", "Second").Op("*").Lit(5)
+	f.Var().Id("defaultAddr").Op("=").Lit(":80")
+	f.Var().Id("defaultShutdownTimeout").Op("=").Qual("time", "Second").Op("*").Lit(5)
+
+	f.Line()
+
+	AddOptions(f, NetHTTP)
+
+	// New service
+	f.Func().Id("New").Params(j.Id("handler").Qual("net/http", "Handler"), j.Id("opts").Op("...").Id("Option")).Add(utils.Jptr).Id("Service").Block(
+		j.Id("httpServer").Op(":=").Add(utils.Rptr).Qual("net/http", "Server").Values(j.Dict{
+			j.Id("Handler"):      j.Id("handler"),
+			j.Id("ReadTimeout"):  j.Id("defaultReadTimeout"),
+			j.Id("WriteTimeout"): j.Id("defaultWriteTimeout"),
+			j.Id("Addr"):         j.Id("defaultAddr"),
+		}),
+		j.Line(),
+		j.Id("s").Op(":=").Add(utils.Rptr).Id("Service").Values(
+			j.Dict{
+				j.Id("server"):          j.Id("httpServer"),
+				j.Id("notify"):          j.Make(j.Chan().Error().Op(",").Lit(1)),
+				j.Id("shutdownTimeout"): j.Id("defaultShutdownTimeout"),
+			},
+		),
+		j.Line(),
+		j.For(j.List(j.Id("_"), j.Id("opt")).Op(":=").Range().Id("opts")).Block(
+			j.Id("opt").Call(j.Id("s")),
+		),
+		j.Line(),
+		j.Id("s").Dot("start").Call(),
+		j.Line(),
+		j.Return(j.Id("s")),
+	)
+
+	f.Line()
+
+	// start()
+	f.Func().Params(j.Id("s").Add(utils.Jptr).Id("Service")).Id("start").Params().Block(
+		j.Id("go").Func().Params().Block(
+			j.If(j.Id("s").Dot("certFile").Op("!=").Lit("").Op("||").Id("s").Dot("keyFile").Op("!=").Lit("")).Block(
+				j.Id("s").Dot("notify").Op("<-").Id("s").Dot("server").Dot("ListenAndServeTLS").Call(j.Id("s").Dot("certFile"), j.Id("s").Dot("keyFile")),
+			).Else().Block(
+				j.Id("s").Dot("notify").Op("<-").Id("s").Dot("server").Dot("ListenAndServe").Call(),
+			),
+			j.Id("close").Call(j.Id("s").Dot("notify")),
+		).Call(),
+	)
+
+	f.Line()
+
+	// Notify()
+	f.Func().Params(j.Id("s").Add(utils.Jptr).Id("Service")).Id("Notify").Params().Op("<-").Chan().Error().Block(
+		j.Return(j.Id("s").Dot("notify")),
+	)
+
+	f.Line()
+
+	// Shutdown()
+	f.Func().Params(j.Id("s").Add(utils.Jptr).Id("Service")).Id("Shutdown").Params().Error().Block(
+		j.List(j.Id("ctx"), j.Id("cancel")).Op(":=").Qual("context", "WithTimeout").Call(
+			j.Qual("context", "Background").Call(),
+			j.Id("s").Dot("shutdownTimeout"),
+		),
+		j.Id("defer").Id("cancel").Call(),
+		j.Line(),
+		j.Return(j.Id("s").Dot("server").Dot("Shutdown").Call(j.Id("ctx"))),
+	)
+
+	return f
+}
+
+// Save - Saves the server.go file of a flavor in the package of its namespace
+func Save(fs afero.Fs, f *j.File, flavor string, ns domain.Namespace, path string) error {
+	// Before saving the file, create the directories if they don't exist
+	outputPath := path + "/" + ns.Package
+	err := fs.MkdirAll(outputPath, os.ModePerm)
+	if err != nil {
+		return &domain.GenerateError{Module: flavor, File: ns.Package, Err: err}
+	}
+
+	err = utils.SaveFile(fs, f, outputPath+"/server.go")
+	if err != nil {
+		return &domain.GenerateError{Module: flavor, File: ns.Package + "/server.go", Err: err}
+	}
+
+	return nil
+}
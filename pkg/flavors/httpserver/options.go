@@ -0,0 +1,184 @@
+package httpserver
+
+import (
+	j "github.com/dave/jennifer/jen"
+
+	"github.com/mahcks/gowizard/pkg/domain"
+)
+
+// Fields are the fields of the generated Service s that its options set, i.e. s.server.Addr
+// An empty field has no option and no config entry, i.e. a gRPC server has no read timeout
+type Fields struct {
+	Addr            string
+	ReadTimeout     string
+	WriteTimeout    string
+	ShutdownTimeout string
+	CertFile        string // TLS needs both files
+	KeyFile         string
+}
+
+// NetHTTP are the fields of the Service that New generates
+var NetHTTP = Fields{
+	Addr:            "s.server.Addr",
+	ReadTimeout:     "s.server.ReadTimeout",
+	WriteTimeout:    "s.server.WriteTimeout",
+	ShutdownTimeout: "s.shutdownTimeout",
+	CertFile:        "s.certFile",
+	KeyFile:         "s.keyFile",
+}
+
+// timeout is an option and config entry of a time.Duration field
+type timeout struct {
+	option  string // name of the option and the field of the config section
+	key     string // key of the config section
+	field   string // field of the Service
+	comment string
+}
+
+func (fields Fields) timeouts() []timeout {
+	var timeouts []timeout
+	for _, t := range []timeout{
+		{"ReadTimeout", "read_timeout", fields.ReadTimeout, "ReadTimeout - Maximum duration for reading a request"},
+		{"WriteTimeout", "write_timeout", fields.WriteTimeout, "WriteTimeout - Maximum duration for writing a response"},
+		{"ShutdownTimeout", "shutdown_timeout", fields.ShutdownTimeout, "ShutdownTimeout - How long Shutdown waits for the pending requests"},
+	} {
+		if t.field != "" {
+			timeouts = append(timeouts, t)
+		}
+	}
+
+	return timeouts
+}
+
+func (fields Fields) tls() bool {
+	return fields.CertFile != "" && fields.KeyFile != ""
+}
+
+// AddOptions - Adds the Option type of the Service and an option for every field to the file
+// Zero values keep the defaults, so a config entry that is left out doesn't change anything
+func AddOptions(f *j.File, fields Fields) {
+	f.Comment("Option configures the Service, zero values keep the defaults")
+	f.Type().Id("Option").Func().Params(j.Op("*").Id("Service"))
+
+	f.Line()
+
+	if fields.Addr != "" {
+		f.Comment("Addr - Address the server listens on, i.e. :8080")
+		f.Func().Id("Addr").Params(j.Id("addr").String()).Id("Option").Block(
+			j.Return(j.Func().Params(j.Id("s").Op("*").Id("Service")).Block(
+				j.If(j.Id("addr").Op("!=").Lit("")).Block(
+					j.Id(fields.Addr).Op("=").Id("addr"),
+				),
+			)),
+		)
+
+		f.Line()
+	}
+
+	for _, t := range fields.timeouts() {
+		f.Comment(t.comment)
+		f.Func().Id(t.option).Params(j.Id("timeout").Qual("time", "Duration")).Id("Option").Block(
+			j.Return(j.Func().Params(j.Id("s").Op("*").Id("Service")).Block(
+				j.If(j.Id("timeout").Op("!=").Lit(0)).Block(
+					j.Id(t.field).Op("=").Id("timeout"),
+				),
+			)),
+		)
+
+		f.Line()
+	}
+
+	if fields.tls() {
+		f.Comment("TLS - Serves TLS with the certificate and key files, the server is plain when both are empty")
+		f.Func().Id("TLS").Params(j.List(j.Id("certFile"), j.Id("keyFile")).String()).Id("Option").Block(
+			j.Return(j.Func().Params(j.Id("s").Op("*").Id("Service")).Block(
+				j.Id(fields.CertFile).Op("=").Id("certFile"),
+				j.Id(fields.KeyFile).Op("=").Id("keyFile"),
+			)),
+		)
+
+		f.Line()
+	}
+}
+
+// NewCall - Calls New of the package with an option for every config entry in internal/app/app.go, cfg is the config section, i.e. cfg.Rest
+// The arguments are put on their own lines, args are the arguments before the options, i.e. the handler
+func NewCall(ns domain.Namespace, cfg string, fields Fields, args ...j.Code) *j.Statement {
+
+	if fields.Addr != "" {
+		args = append(args, j.Qual(ns.Path(), "Addr").Call(j.Id(cfg+".Addr")))
+	}
+
+	for _, t := range fields.timeouts() {
+		args = append(args, j.Qual(ns.Path(), t.option).Call(j.Id(cfg+"."+t.option)))
+	}
+
+	if fields.tls() {
+		args = append(args, j.Qual(ns.Path(), "TLS").Call(j.Id(cfg+".TLS.CertFile"), j.Id(cfg+".TLS.KeyFile")))
+	}
+
+	return j.Qual(ns.Path(), "New").Custom(j.Options{Open: "(", Close: ")", Separator: ",", Multi: true}, args...)
+}
+
+// AppSelect - The select branch in internal/app/app.go that logs the error the server of the namespace stopped with
+func AppSelect(ns domain.Namespace) j.Code {
+	return j.Case(
+		j.Id("err").Op("=").Op("<-").Id(ns.Id("server")).Dot("Notify").Call()).Block(
+		ns.Log("Error", "app."+ns.Id("server")+".Notify()", j.Lit("error"), j.Err()),
+	)
+}
+
+// AppShutdown - Shuts the server of the namespace down at the end of Run() in internal/app/app.go and logs the error
+func AppShutdown(ns domain.Namespace) []j.Code {
+	return []j.Code{
+		j.Id("err").Op("=").Id(ns.Id("server")).Dot("Shutdown").Call(),
+		j.Line(),
+		j.If(j.Id("err").Op("!=").Nil()).Block(
+			ns.Log("Error", "app."+ns.Id("server")+".Shutdown()", j.Lit("error"), j.Err()),
+		),
+	}
+}
+
+// ConfigGo - The config section of a server with an entry for every field, id is its field in the Config struct and key its key, i.e. Rest and rest
+func ConfigGo(id, key string, fields Fields) *j.Statement {
+	var entries []j.Code
+
+	if fields.Addr != "" {
+		entries = append(entries, j.Id("Addr").String().Tag(map[string]string{"mapstructure": "addr", "json": "addr"}))
+	}
+
+	for _, t := range fields.timeouts() {
+		entries = append(entries, j.Id(t.option).Qual("time", "Duration").Tag(map[string]string{"mapstructure": t.key, "json": t.key}))
+	}
+
+	if fields.tls() {
+		entries = append(entries, j.Id("TLS").Struct(
+			j.Id("CertFile").String().Tag(map[string]string{"mapstructure": "cert_file", "json": "cert_file"}),
+			j.Id("KeyFile").String().Tag(map[string]string{"mapstructure": "key_file", "json": "key_file"}),
+		).Tag(map[string]string{"mapstructure": "tls", "json": "tls"}))
+	}
+
+	return j.Id(id).Struct(entries...).Tag(map[string]string{"mapstructure": key, "json": key})
+}
+
+// ConfigYAML - The config section of a server in YAML format, the timeouts are 5s and TLS is off
+func ConfigYAML(key, addr string, fields Fields) map[string]interface{} {
+	section := map[string]interface{}{}
+
+	if fields.Addr != "" {
+		section["addr"] = addr
+	}
+
+	for _, t := range fields.timeouts() {
+		section[t.key] = "5s"
+	}
+
+	if fields.tls() {
+		section["tls"] = map[string]interface{}{
+			"cert_file": "",
+			"key_file":  "",
+		}
+	}
+
+	return map[string]interface{}{key: section}
+}
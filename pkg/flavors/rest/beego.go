@@ -1,12 +1,11 @@
 package services
 
 import (
-	"os"
-
 	j "github.com/dave/jennifer/jen"
+	"github.com/spf13/afero"
 
 	"github.com/mahcks/gowizard/pkg/domain"
-	"github.com/mahcks/gowizard/pkg/utils"
+	"github.com/mahcks/gowizard/pkg/flavors/httpserver"
 )
 
 type BeegoFlavor struct {
@@ -40,45 +39,42 @@ func NewBeegoFlavor() domain.FlavorI {
 
 // ConfigYAML is the configuration of the adapter in YAML format
 func (flv *BeegoFlavor) ConfigYAML() map[string]interface{} {
-	return nil
+	return httpserver.ConfigYAML("rest", ":8080", httpserver.NetHTTP)
 }
 
 // ConfigGo is the configuration of the adapter in Go format
 func (flv *BeegoFlavor) ConfigGo() *j.Statement {
-	return nil
+	return httpserver.ConfigGo("Rest", "rest", httpserver.NetHTTP)
 }
 
 // AppInit is the code that will be added to the START internal/app/app.go Run() function
-func (flv *BeegoFlavor) AppInit(module string) []j.Code {
-	return nil
+// The controller register of beego is an http.Handler, so it's served by the net/http server of the package
+func (flv *BeegoFlavor) AppInit(ns domain.Namespace) []j.Code {
+	return []j.Code{
+		j.Id(ns.Id("handler")).Op(":=").Qual("github.com/beego/beego/v2/server/web", "NewControllerRegister").Call(),
+		j.Line(),
+		j.Id(ns.Id("server")).Op(":=").Add(httpserver.NewCall(ns, "cfg.Rest", httpserver.NetHTTP, j.Id(ns.Id("handler")))),
+	}
 }
 
-func (flv *BeegoFlavor) AppSelect(module string) j.Code {
-	return nil
+// AppSelect is the branch that will be added to the select of the internal/app/app.go Run() function
+func (flv *BeegoFlavor) AppSelect(ns domain.Namespace) j.Code {
+	return httpserver.AppSelect(ns)
 }
 
 // AppShutdown is the code that will be added to the END internal/app/app.go Run() function
-func (flv *BeegoFlavor) AppShutdown(module string) []j.Code {
-	return nil
+func (flv *BeegoFlavor) AppShutdown(ns domain.Namespace) []j.Code {
+	return httpserver.AppShutdown(ns)
 }
 
-// Service is the code that will be added to its own `pkg` folder
-func (flv *BeegoFlavor) Service(module, path string) *j.File {
-	f := j.NewFilePathName(module+"/pkg/httpserver", "httpserver")
-
-	// Before saving the file, create the directories if they don't exist
-	outputPath := path + "/pkg/httpserver"
-	err := os.MkdirAll(outputPath, os.ModePerm)
-	if err != nil {
-		utils.PrintError("error creating directories: %s", err)
-		return nil
-	}
-
-	err = f.Save(outputPath + "/server.go")
-	if err != nil {
-		utils.PrintError("error saving file: %s", err)
-		return nil
+// Constraints - what the flavor needs from the rest of the project
+func (flv *BeegoFlavor) Constraints() domain.Constraints {
+	return domain.Constraints{
+		Packages: []string{"pkg/httpserver"},
 	}
+}
 
-	return f
+// Service is the code that will be added to the package of its namespace
+func (flv *BeegoFlavor) Service(fs afero.Fs, ns domain.Namespace, path string) error {
+	return httpserver.Save(fs, httpserver.New(ns), flv.name, ns, path)
 }
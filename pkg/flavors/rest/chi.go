@@ -0,0 +1,112 @@
+package services
+
+import (
+	j "github.com/dave/jennifer/jen"
+	"github.com/spf13/afero"
+
+	"github.com/mahcks/gowizard/pkg/domain"
+	"github.com/mahcks/gowizard/pkg/flavors/httpserver"
+	"github.com/mahcks/gowizard/pkg/utils"
+)
+
+type ChiFlavor struct {
+	name        string // name of the flavor
+	displayName string // name of the adapter that will be displayed in the CLI
+	description string // description of the flavor
+}
+
+// GetName returns the name of the flavor
+func (flv *ChiFlavor) GetName() string {
+	return flv.name
+}
+
+// GetDisplayName - what will be displayed in the CLI when prompted
+func (flv *ChiFlavor) GetDisplayName() string {
+	return flv.displayName
+}
+
+// GetDescription - returns the description of the flavor
+func (flv *ChiFlavor) GetDescription() string {
+	return flv.description
+}
+
+func NewChiFlavor() domain.FlavorI {
+	return &ChiFlavor{
+		name:        "chi",
+		displayName: "go-chi/chi",
+		description: "chi is a lightweight, idiomatic and composable router for building Go HTTP services.",
+	}
+}
+
+// ConfigYAML is the configuration of the adapter in YAML format
+func (flv *ChiFlavor) ConfigYAML() map[string]interface{} {
+	return httpserver.ConfigYAML("rest", ":8080", httpserver.NetHTTP)
+}
+
+// ConfigGo is the configuration of the adapter in Go format
+func (flv *ChiFlavor) ConfigGo() *j.Statement {
+	return httpserver.ConfigGo("Rest", "rest", httpserver.NetHTTP)
+}
+
+// AppInit is the code that will be added to the START internal/app/app.go Run() function
+func (flv *ChiFlavor) AppInit(ns domain.Namespace) []j.Code {
+	return []j.Code{
+		j.Id(ns.Id("handler")).Op(":=").Qual(ns.Path(), "NewRouter").Call(),
+		j.Line(),
+		j.Id(ns.Id("server")).Op(":=").Add(httpserver.NewCall(ns, "cfg.Rest", httpserver.NetHTTP, j.Id(ns.Id("handler")))),
+	}
+}
+
+// AppSelect is the branch that will be added to the select of the internal/app/app.go Run() function
+func (flv *ChiFlavor) AppSelect(ns domain.Namespace) j.Code {
+	return httpserver.AppSelect(ns)
+}
+
+// AppShutdown is the code that will be added to the END internal/app/app.go Run() function
+func (flv *ChiFlavor) AppShutdown(ns domain.Namespace) []j.Code {
+	return httpserver.AppShutdown(ns)
+}
+
+// Constraints - what the flavor needs from the rest of the project
+func (flv *ChiFlavor) Constraints() domain.Constraints {
+	return domain.Constraints{
+		Packages: []string{"pkg/httpserver"},
+	}
+}
+
+// Service is the code that will be added to the package of its namespace
+func (flv *ChiFlavor) Service(fs afero.Fs, ns domain.Namespace, path string) error {
+	err := httpserver.Save(fs, httpserver.New(ns), flv.name, ns, path)
+	if err != nil {
+		return err
+	}
+
+	// router.go
+	f := j.NewFilePathName(ns.Path(), ns.PackageName())
+	f.ImportAlias("github.com/go-chi/chi/v5", "chi")
+
+	f.Comment("NewRouter - The chi router with the routes of the API, it's served by the http.Server of the package")
+	f.Func().Id("NewRouter").Params().Qual("github.com/go-chi/chi/v5", "Router").Block(
+		j.Id("r").Op(":=").Qual("github.com/go-chi/chi/v5", "NewRouter").Call(),
+		j.Id("r").Dot("Use").Call(j.Qual("github.com/go-chi/chi/v5/middleware", "Recoverer")),
+		j.Line(),
+		j.Id("r").Dot("Route").Call(j.Lit("/api/v1"), j.Func().Params(j.Id("r").Qual("github.com/go-chi/chi/v5", "Router")).Block(
+			j.Id("r").Dot("Get").Call(j.Lit("/hello/{name}"), j.Id("hello")),
+		)),
+		j.Line(),
+		j.Return(j.Id("r")),
+	)
+
+	f.Line()
+
+	f.Func().Id("hello").Params(j.Id("w").Qual("net/http", "ResponseWriter"), j.Id("r").Add(utils.Jptr).Qual("net/http", "Request")).Block(
+		j.Qual("fmt", "Fprintf").Call(j.Id("w"), j.Lit("Hello, %s!\n"), j.Qual("github.com/go-chi/chi/v5", "URLParam").Call(j.Id("r"), j.Lit("name"))),
+	)
+
+	err = utils.SaveFile(fs, f, path+"/"+ns.Package+"/router.go")
+	if err != nil {
+		return &domain.GenerateError{Module: flv.name, File: ns.Package + "/router.go", Err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	j "github.com/dave/jennifer/jen"
+	"github.com/spf13/afero"
+
+	"github.com/mahcks/gowizard/pkg/domain"
+	"github.com/mahcks/gowizard/pkg/flavors/httpserver"
+	"github.com/mahcks/gowizard/pkg/utils"
+)
+
+type EchoFlavor struct {
+	name        string // name of the flavor
+	displayName string // name of the adapter that will be displayed in the CLI
+	description string // description of the flavor
+}
+
+// GetName returns the name of the flavor
+func (flv *EchoFlavor) GetName() string {
+	return flv.name
+}
+
+// GetDisplayName - what will be displayed in the CLI when prompted
+func (flv *EchoFlavor) GetDisplayName() string {
+	return flv.displayName
+}
+
+// GetDescription - returns the description of the flavor
+func (flv *EchoFlavor) GetDescription() string {
+	return flv.description
+}
+
+func NewEchoFlavor() domain.FlavorI {
+	return &EchoFlavor{
+		name:        "echo",
+		displayName: "labstack/echo",
+		description: "High performance, extensible, minimalist Go web framework.",
+	}
+}
+
+// ConfigYAML is the configuration of the adapter in YAML format
+func (flv *EchoFlavor) ConfigYAML() map[string]interface{} {
+	return httpserver.ConfigYAML("rest", ":8080", httpserver.NetHTTP)
+}
+
+// ConfigGo is the configuration of the adapter in Go format
+func (flv *EchoFlavor) ConfigGo() *j.Statement {
+	return httpserver.ConfigGo("Rest", "rest", httpserver.NetHTTP)
+}
+
+// AppInit is the code that will be added to the START internal/app/app.go Run() function
+func (flv *EchoFlavor) AppInit(ns domain.Namespace) []j.Code {
+	return []j.Code{
+		j.Id(ns.Id("handler")).Op(":=").Qual(ns.Path(), "NewRouter").Call(),
+		j.Line(),
+		j.Id(ns.Id("server")).Op(":=").Add(httpserver.NewCall(ns, "cfg.Rest", httpserver.NetHTTP, j.Id(ns.Id("handler")))),
+	}
+}
+
+// AppSelect is the branch that will be added to the select of the internal/app/app.go Run() function
+func (flv *EchoFlavor) AppSelect(ns domain.Namespace) j.Code {
+	return httpserver.AppSelect(ns)
+}
+
+// AppShutdown is the code that will be added to the END internal/app/app.go Run() function
+func (flv *EchoFlavor) AppShutdown(ns domain.Namespace) []j.Code {
+	return httpserver.AppShutdown(ns)
+}
+
+// Constraints - what the flavor needs from the rest of the project
+func (flv *EchoFlavor) Constraints() domain.Constraints {
+	return domain.Constraints{
+		Packages: []string{"pkg/httpserver"},
+	}
+}
+
+// Service is the code that will be added to the package of its namespace
+func (flv *EchoFlavor) Service(fs afero.Fs, ns domain.Namespace, path string) error {
+	err := httpserver.Save(fs, httpserver.New(ns), flv.name, ns, path)
+	if err != nil {
+		return err
+	}
+
+	// router.go
+	f := j.NewFilePathName(ns.Path(), ns.PackageName())
+	f.ImportAlias("github.com/labstack/echo/v4", "echo")
+
+	f.Comment("NewRouter - The echo instance with the routes of the API, it's served by the http.Server of the package")
+	f.Func().Id("NewRouter").Params().Add(utils.Jptr).Qual("github.com/labstack/echo/v4", "Echo").Block(
+		j.Id("e").Op(":=").Qual("github.com/labstack/echo/v4", "New").Call(),
+		j.Line(),
+		j.Id("v1").Op(":=").Id("e").Dot("Group").Call(j.Lit("/api/v1")),
+		j.Id("v1").Dot("GET").Call(j.Lit("/hello/:name"), j.Id("hello")),
+		j.Line(),
+		j.Return(j.Id("e")),
+	)
+
+	f.Line()
+
+	f.Func().Id("hello").Params(j.Id("c").Qual("github.com/labstack/echo/v4", "Context")).Error().Block(
+		j.Return(j.Id("c").Dot("String").Call(j.Qual("net/http", "StatusOK"), j.Lit("Hello, ").Op("+").Id("c").Dot("Param").Call(j.Lit("name")).Op("+").Lit("!"))),
+	)
+
+	err = utils.SaveFile(fs, f, path+"/"+ns.Package+"/router.go")
+	if err != nil {
+		return &domain.GenerateError{Module: flv.name, File: ns.Package + "/router.go", Err: err}
+	}
+
+	return nil
+}
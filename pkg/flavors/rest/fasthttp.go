@@ -1,11 +1,11 @@
 package services
 
 import (
-	"os"
-
 	j "github.com/dave/jennifer/jen"
+	"github.com/spf13/afero"
 
 	"github.com/mahcks/gowizard/pkg/domain"
+	"github.com/mahcks/gowizard/pkg/flavors/httpserver"
 	"github.com/mahcks/gowizard/pkg/utils"
 )
 
@@ -38,85 +38,105 @@ func NewFastHTTPFlavor() domain.FlavorI {
 	}
 }
 
+// fasthttpFields are the fields of the Service the options set
+var fasthttpFields = httpserver.Fields{
+	Addr:            "s.addr",
+	ReadTimeout:     "s.server.ReadTimeout",
+	WriteTimeout:    "s.server.WriteTimeout",
+	ShutdownTimeout: "s.shutdownTimeout",
+	CertFile:        "s.certFile",
+	KeyFile:         "s.keyFile",
+}
+
 // ConfigYAML is the configuration of the adapter in YAML format
 func (flv *FastHTTPFlavor) ConfigYAML() map[string]interface{} {
-	return nil
+	return httpserver.ConfigYAML("rest", ":8080", fasthttpFields)
 }
 
 // ConfigGo is the configuration of the adapter in Go format
 func (flv *FastHTTPFlavor) ConfigGo() *j.Statement {
-	return nil
+	return httpserver.ConfigGo("Rest", "rest", fasthttpFields)
 }
 
 // AppInit is the code that will be added to the START internal/app/app.go Run() function
-func (flv *FastHTTPFlavor) AppInit(module string) []j.Code {
+func (flv *FastHTTPFlavor) AppInit(ns domain.Namespace) []j.Code {
 	return []j.Code{
-		j.Id("handler").Op(":=").Qual("github.com/fasthttp/router", "New").Call(),
+		j.Id(ns.Id("handler")).Op(":=").Qual("github.com/fasthttp/router", "New").Call(),
 		j.Line(),
-		j.Id("httpServer").Op(":=").Qual(module+"/pkg/httpserver", "New").Call(j.Id("handler").Dot("Handler")),
+		j.Id(ns.Id("server")).Op(":=").Add(httpserver.NewCall(ns, "cfg.Rest", fasthttpFields, j.Id(ns.Id("handler")).Dot("Handler"))),
 	}
 }
 
-func (flv *FastHTTPFlavor) AppSelect(module string) j.Code {
-	return j.Case(
-		j.Id("err").Op("=").Op("<-").Id("httpServer").Dot("Notify").Call()).Block(
-		j.Qual("fmt", "Println").Call(j.Lit("app.httpServer.Notifiy()"), j.Err()),
-	)
+// AppSelect is the branch that will be added to the select of the internal/app/app.go Run() function
+func (flv *FastHTTPFlavor) AppSelect(ns domain.Namespace) j.Code {
+	return httpserver.AppSelect(ns)
 }
 
 // AppShutdown is the code that will be added to the END internal/app/app.go Run() function
-func (flv *FastHTTPFlavor) AppShutdown(module string) []j.Code {
-	return []j.Code{
-		j.Id("err").Op("=").Id("httpServer").Dot("Shutdown").Call(),
-		j.Line(),
-		j.If(j.Id("err").Op("!=").Nil()).Block(
-			j.Qual("fmt", "Println").Call(j.Lit("app.httpServer.Shutdown()"), j.Err()),
-		),
-	}
+func (flv *FastHTTPFlavor) AppShutdown(ns domain.Namespace) []j.Code {
+	return httpserver.AppShutdown(ns)
 }
 
-// Service is the code that will be added to its own `pkg` folder
-func (flv *FastHTTPFlavor) Service(module, path string) *j.File {
-	f := j.NewFilePathName(module+"/pkg/httpserver", "httpserver")
-
-	// Before saving the file, create the directories if they don't exist
-	outputPath := path + "/pkg/httpserver"
-	err := os.MkdirAll(outputPath, os.ModePerm)
-	if err != nil {
-		utils.PrintError("error creating directories: %s", err)
-		return nil
+// Constraints - what the flavor needs from the rest of the project
+func (flv *FastHTTPFlavor) Constraints() domain.Constraints {
+	return domain.Constraints{
+		GoVersion: "1.20", // Minimum of fasthttp
+		Packages:  []string{"pkg/httpserver"},
 	}
+}
+
+// Service is the code that will be added to the package of its namespace
+func (flv *FastHTTPFlavor) Service(fs afero.Fs, ns domain.Namespace, path string) error {
+	f := j.NewFilePathName(ns.Path(), ns.PackageName())
 
 	// Service struct
 	sStruct := j.Type().Id("Service").Struct(
 		j.Id("server").Add(utils.Jptr).Qual("github.com/valyala/fasthttp", "Server"),
+		j.Id("addr").String(),
 		j.Id("notify").Chan().Error(),
 		j.Id("shutdownTimeout").Qual("time", "Duration"),
+		j.Id("certFile").String(),
+		j.Id("keyFile").String(),
 	)
 
 	f.Add(sStruct)
 
 	f.Var().Id("defaultReadTimeout").Op("=").Qual("time", "Second").Op("*").Lit(5)
 	f.Var().Id("defaultWriteTimeout").Op("=").Qual("time", "Second").Op("*").Lit(5)
+	f.Var().Id("defaultIdleTimeout").Op("=").Qual("time", "Second").Op("*").Lit(60)
 	f.Var().Id("defaultAddr").Op("=").Lit("0.0.0.0:80")
 	f.Var().Id("defaultShutdownTimeout").Op("=").Qual("time", "Second").Op("*").Lit(5)
 
+	f.Line()
+
+	httpserver.AddOptions(f, fasthttpFields)
+
 	// New service
-	f.Func().Id("New").Params(j.Id("handler").Qual("github.com/valyala/fasthttp", "RequestHandler")).Add(utils.Jptr).Id("Service").Block(
+	f.Func().Id("New").Params(
+		j.Id("handler").Qual("github.com/valyala/fasthttp", "RequestHandler"),
+		j.Id("opts").Op("...").Id("Option"),
+	).Add(utils.Jptr).Id("Service").Block(
+		j.Comment("ShutdownWithContext doesn't close keep-alive connections, the idle timeout closes them"),
 		j.Id("httpServer").Op(":=").Add(utils.Rptr).Qual("github.com/valyala/fasthttp", "Server").Values(j.Dict{
 			j.Id("Handler"):      j.Id("handler"),
 			j.Id("ReadTimeout"):  j.Id("defaultReadTimeout"),
 			j.Id("WriteTimeout"): j.Id("defaultWriteTimeout"),
+			j.Id("IdleTimeout"):  j.Id("defaultIdleTimeout"),
 		}),
 		j.Line(),
 		j.Id("s").Op(":=").Add(utils.Rptr).Id("Service").Values(
 			j.Dict{
 				j.Id("server"):          j.Id("httpServer"),
+				j.Id("addr"):            j.Id("defaultAddr"),
 				j.Id("notify"):          j.Make(j.Chan().Error().Op(",").Lit(1)),
 				j.Id("shutdownTimeout"): j.Id("defaultShutdownTimeout"),
 			},
 		),
 		j.Line(),
+		j.For(j.List(j.Id("_"), j.Id("opt")).Op(":=").Range().Id("opts")).Block(
+			j.Id("opt").Call(j.Id("s")),
+		),
+		j.Line(),
 		j.Id("s").Dot("start").Call(),
 		j.Line(),
 		j.Return(j.Id("s")),
@@ -127,7 +147,11 @@ func (flv *FastHTTPFlavor) Service(module, path string) *j.File {
 	// start()
 	f.Func().Params(j.Id("s").Add(utils.Jptr).Id("Service")).Id("start").Params().Block(
 		j.Id("go").Func().Params().Block(
-			j.Id("s").Dot("notify").Op("<-").Id("s").Dot("server").Dot("ListenAndServe").Call(j.Id("defaultAddr")),
+			j.If(j.Id("s").Dot("certFile").Op("!=").Lit("").Op("||").Id("s").Dot("keyFile").Op("!=").Lit("")).Block(
+				j.Id("s").Dot("notify").Op("<-").Id("s").Dot("server").Dot("ListenAndServeTLS").Call(j.Id("s").Dot("addr"), j.Id("s").Dot("certFile"), j.Id("s").Dot("keyFile")),
+			).Else().Block(
+				j.Id("s").Dot("notify").Op("<-").Id("s").Dot("server").Dot("ListenAndServe").Call(j.Id("s").Dot("addr")),
+			),
 			j.Id("close").Call(j.Id("s").Dot("notify")),
 		).Call(),
 	)
@@ -143,14 +167,14 @@ func (flv *FastHTTPFlavor) Service(module, path string) *j.File {
 
 	// Shutdown()
 	f.Func().Params(j.Id("s").Add(utils.Jptr).Id("Service")).Id("Shutdown").Params().Error().Block(
-		j.Return(j.Id("s").Dot("server").Dot("Shutdown").Call()),
+		j.List(j.Id("ctx"), j.Id("cancel")).Op(":=").Qual("context", "WithTimeout").Call(
+			j.Qual("context", "Background").Call(),
+			j.Id("s").Dot("shutdownTimeout"),
+		),
+		j.Id("defer").Id("cancel").Call(),
+		j.Line(),
+		j.Return(j.Id("s").Dot("server").Dot("ShutdownWithContext").Call(j.Id("ctx"))),
 	)
 
-	err = f.Save(outputPath + "/server.go")
-	if err != nil {
-		utils.PrintError("error saving file: %s", err)
-		return nil
-	}
-
-	return f
+	return httpserver.Save(fs, f, flv.name, ns, path)
 }
@@ -1,11 +1,11 @@
 package services
 
 import (
-	"os"
-
 	j "github.com/dave/jennifer/jen"
+	"github.com/spf13/afero"
 
 	"github.com/mahcks/gowizard/pkg/domain"
+	"github.com/mahcks/gowizard/pkg/flavors/httpserver"
 	"github.com/mahcks/gowizard/pkg/utils"
 )
 
@@ -38,47 +38,135 @@ func NewFiberFlavor() domain.FlavorI {
 	}
 }
 
+// fiberFields are the fields of the Service the options set, the timeouts are the ones of the fasthttp server of the app
+var fiberFields = httpserver.Fields{
+	Addr:            "s.addr",
+	ReadTimeout:     "s.app.Server().ReadTimeout",
+	WriteTimeout:    "s.app.Server().WriteTimeout",
+	ShutdownTimeout: "s.shutdownTimeout",
+	CertFile:        "s.certFile",
+	KeyFile:         "s.keyFile",
+}
+
 // ConfigYAML is the configuration of the adapter in YAML format
 func (flv *FiberFlavor) ConfigYAML() map[string]interface{} {
-	return nil
+	return httpserver.ConfigYAML("rest", ":8080", fiberFields)
 }
 
 // ConfigGo is the configuration of the adapter in Go format
 func (flv *FiberFlavor) ConfigGo() *j.Statement {
-	return nil
+	return httpserver.ConfigGo("Rest", "rest", fiberFields)
 }
 
 // AppInit is the code that will be added to the START internal/app/app.go Run() function
-func (flv *FiberFlavor) AppInit(module string) []j.Code {
-	return nil
+func (flv *FiberFlavor) AppInit(ns domain.Namespace) []j.Code {
+	return []j.Code{
+		j.Id(ns.Id("handler")).Op(":=").Qual("github.com/gofiber/fiber/v2", "New").Call(),
+		j.Line(),
+		j.Id(ns.Id("server")).Op(":=").Add(httpserver.NewCall(ns, "cfg.Rest", fiberFields, j.Id(ns.Id("handler")))),
+	}
 }
 
-func (flv *FiberFlavor) AppSelect(module string) j.Code {
-	return nil
+// AppSelect is the branch that will be added to the select of the internal/app/app.go Run() function
+func (flv *FiberFlavor) AppSelect(ns domain.Namespace) j.Code {
+	return httpserver.AppSelect(ns)
 }
 
 // AppShutdown is the code that will be added to the END internal/app/app.go Run() function
-func (flv *FiberFlavor) AppShutdown(module string) []j.Code {
-	return nil
+func (flv *FiberFlavor) AppShutdown(ns domain.Namespace) []j.Code {
+	return httpserver.AppShutdown(ns)
 }
 
-// Service is the code that will be added to its own `pkg` folder
-func (flv *FiberFlavor) Service(module, path string) *j.File {
-	f := j.NewFilePathName(module+"/pkg/httpserver", "httpserver")
-
-	// Before saving the file, create the directories if they don't exist
-	outputPath := path + "/pkg/httpserver"
-	err := os.MkdirAll(outputPath, os.ModePerm)
-	if err != nil {
-		utils.PrintError("error creating directories: %s", err)
-		return nil
-	}
-
-	err = f.Save(outputPath + "/server.go")
-	if err != nil {
-		utils.PrintError("error saving file: %s", err)
-		return nil
+// Constraints - what the flavor needs from the rest of the project
+func (flv *FiberFlavor) Constraints() domain.Constraints {
+	return domain.Constraints{
+		GoVersion: "1.20", // Minimum of fiber v2
+		Packages:  []string{"pkg/httpserver"},
 	}
+}
 
-	return f
+// Service is the code that will be added to the package of its namespace
+func (flv *FiberFlavor) Service(fs afero.Fs, ns domain.Namespace, path string) error {
+	f := j.NewFilePathName(ns.Path(), ns.PackageName())
+
+	// Service struct
+	sStruct := j.Type().Id("Service").Struct(
+		j.Id("app").Add(utils.Jptr).Qual("github.com/gofiber/fiber/v2", "App"),
+		j.Id("addr").String(),
+		j.Id("notify").Chan().Error(),
+		j.Id("shutdownTimeout").Qual("time", "Duration"),
+		j.Id("certFile").String(),
+		j.Id("keyFile").String(),
+	)
+
+	f.Add(sStruct)
+
+	f.Var().Id("defaultReadTimeout").Op("=").Qual("time", "Second").Op("*").Lit(5)
+	f.Var().Id("defaultWriteTimeout").Op("=").Qual("time", "Second").Op("*").Lit(5)
+	f.Var().Id("defaultAddr").Op("=").Lit(":80")
+	f.Var().Id("defaultShutdownTimeout").Op("=").Qual("time", "Second").Op("*").Lit(5)
+
+	f.Line()
+
+	httpserver.AddOptions(f, fiberFields)
+
+	// New service
+	f.Func().Id("New").Params(j.Id("app").Add(utils.Jptr).Qual("github.com/gofiber/fiber/v2", "App"), j.Id("opts").Op("...").Id("Option")).Add(utils.Jptr).Id("Service").Block(
+		j.Comment("fiber.New creates the fasthttp server, the timeouts are set on it before it starts listening"),
+		j.Id("app").Dot("Server").Call().Dot("ReadTimeout").Op("=").Id("defaultReadTimeout"),
+		j.Id("app").Dot("Server").Call().Dot("WriteTimeout").Op("=").Id("defaultWriteTimeout"),
+		j.Line(),
+		j.Id("s").Op(":=").Add(utils.Rptr).Id("Service").Values(
+			j.Dict{
+				j.Id("app"):             j.Id("app"),
+				j.Id("addr"):            j.Id("defaultAddr"),
+				j.Id("notify"):          j.Make(j.Chan().Error().Op(",").Lit(1)),
+				j.Id("shutdownTimeout"): j.Id("defaultShutdownTimeout"),
+			},
+		),
+		j.Line(),
+		j.For(j.List(j.Id("_"), j.Id("opt")).Op(":=").Range().Id("opts")).Block(
+			j.Id("opt").Call(j.Id("s")),
+		),
+		j.Line(),
+		j.Id("s").Dot("start").Call(),
+		j.Line(),
+		j.Return(j.Id("s")),
+	)
+
+	f.Line()
+
+	// start()
+	f.Func().Params(j.Id("s").Add(utils.Jptr).Id("Service")).Id("start").Params().Block(
+		j.Id("go").Func().Params().Block(
+			j.If(j.Id("s").Dot("certFile").Op("!=").Lit("").Op("||").Id("s").Dot("keyFile").Op("!=").Lit("")).Block(
+				j.Id("s").Dot("notify").Op("<-").Id("s").Dot("app").Dot("ListenTLS").Call(j.Id("s").Dot("addr"), j.Id("s").Dot("certFile"), j.Id("s").Dot("keyFile")),
+			).Else().Block(
+				j.Id("s").Dot("notify").Op("<-").Id("s").Dot("app").Dot("Listen").Call(j.Id("s").Dot("addr")),
+			),
+			j.Id("close").Call(j.Id("s").Dot("notify")),
+		).Call(),
+	)
+
+	f.Line()
+
+	// Notify()
+	f.Func().Params(j.Id("s").Add(utils.Jptr).Id("Service")).Id("Notify").Params().Op("<-").Chan().Error().Block(
+		j.Return(j.Id("s").Dot("notify")),
+	)
+
+	f.Line()
+
+	// Shutdown()
+	f.Func().Params(j.Id("s").Add(utils.Jptr).Id("Service")).Id("Shutdown").Params().Error().Block(
+		j.List(j.Id("ctx"), j.Id("cancel")).Op(":=").Qual("context", "WithTimeout").Call(
+			j.Qual("context", "Background").Call(),
+			j.Id("s").Dot("shutdownTimeout"),
+		),
+		j.Id("defer").Id("cancel").Call(),
+		j.Line(),
+		j.Return(j.Id("s").Dot("app").Dot("ShutdownWithContext").Call(j.Id("ctx"))),
+	)
+
+	return httpserver.Save(fs, f, flv.name, ns, path)
 }
@@ -0,0 +1,116 @@
+package services
+
+import (
+	j "github.com/dave/jennifer/jen"
+	"github.com/spf13/afero"
+
+	"github.com/mahcks/gowizard/pkg/domain"
+	"github.com/mahcks/gowizard/pkg/flavors/httpserver"
+	"github.com/mahcks/gowizard/pkg/utils"
+)
+
+type StdlibFlavor struct {
+	name        string // name of the flavor
+	displayName string // name of the adapter that will be displayed in the CLI
+	description string // description of the flavor
+}
+
+// GetName returns the name of the flavor
+func (flv *StdlibFlavor) GetName() string {
+	return flv.name
+}
+
+// GetDisplayName - what will be displayed in the CLI when prompted
+func (flv *StdlibFlavor) GetDisplayName() string {
+	return flv.displayName
+}
+
+// GetDescription - returns the description of the flavor
+func (flv *StdlibFlavor) GetDescription() string {
+	return flv.description
+}
+
+func NewStdlibFlavor() domain.FlavorI {
+	return &StdlibFlavor{
+		name:        "stdlib",
+		displayName: "net/http",
+		description: "The standard library http.ServeMux with method and path-pattern routing, without any third-party dependencies.",
+	}
+}
+
+// ConfigYAML is the configuration of the adapter in YAML format
+func (flv *StdlibFlavor) ConfigYAML() map[string]interface{} {
+	return httpserver.ConfigYAML("rest", ":8080", httpserver.NetHTTP)
+}
+
+// ConfigGo is the configuration of the adapter in Go format
+func (flv *StdlibFlavor) ConfigGo() *j.Statement {
+	return httpserver.ConfigGo("Rest", "rest", httpserver.NetHTTP)
+}
+
+// AppInit is the code that will be added to the START internal/app/app.go Run() function
+func (flv *StdlibFlavor) AppInit(ns domain.Namespace) []j.Code {
+	return []j.Code{
+		j.Id(ns.Id("handler")).Op(":=").Qual(ns.Path(), "NewRouter").Call(),
+		j.Line(),
+		j.Id(ns.Id("server")).Op(":=").Add(httpserver.NewCall(ns, "cfg.Rest", httpserver.NetHTTP, j.Id(ns.Id("handler")))),
+	}
+}
+
+// AppSelect is the branch that will be added to the select of the internal/app/app.go Run() function
+func (flv *StdlibFlavor) AppSelect(ns domain.Namespace) j.Code {
+	return httpserver.AppSelect(ns)
+}
+
+// AppShutdown is the code that will be added to the END internal/app/app.go Run() function
+func (flv *StdlibFlavor) AppShutdown(ns domain.Namespace) []j.Code {
+	return httpserver.AppShutdown(ns)
+}
+
+// Constraints - what the flavor needs from the rest of the project
+func (flv *StdlibFlavor) Constraints() domain.Constraints {
+	return domain.Constraints{
+		GoVersion: "1.22", // Methods and wildcards in http.ServeMux patterns
+		Packages:  []string{"pkg/httpserver"},
+	}
+}
+
+// Service is the code that will be added to the package of its namespace
+func (flv *StdlibFlavor) Service(fs afero.Fs, ns domain.Namespace, path string) error {
+	err := httpserver.Save(fs, httpserver.New(ns), flv.name, ns, path)
+	if err != nil {
+		return err
+	}
+
+	// router.go
+	f := j.NewFilePathName(ns.Path(), ns.PackageName())
+
+	f.Comment("NewRouter - Routes requests by method and path pattern, i.e. GET /hello/{name}")
+	f.Func().Id("NewRouter").Params().Add(utils.Jptr).Qual("net/http", "ServeMux").Block(
+		j.Id("mux").Op(":=").Qual("net/http", "NewServeMux").Call(),
+		j.Line(),
+		j.Id("mux").Dot("HandleFunc").Call(j.Lit("GET /health"), j.Id("health")),
+		j.Id("mux").Dot("HandleFunc").Call(j.Lit("GET /hello/{name}"), j.Id("hello")),
+		j.Line(),
+		j.Return(j.Id("mux")),
+	)
+
+	f.Line()
+
+	f.Func().Id("health").Params(j.Id("w").Qual("net/http", "ResponseWriter"), j.Id("r").Add(utils.Jptr).Qual("net/http", "Request")).Block(
+		j.Id("w").Dot("WriteHeader").Call(j.Qual("net/http", "StatusOK")),
+	)
+
+	f.Line()
+
+	f.Func().Id("hello").Params(j.Id("w").Qual("net/http", "ResponseWriter"), j.Id("r").Add(utils.Jptr).Qual("net/http", "Request")).Block(
+		j.Qual("fmt", "Fprintf").Call(j.Id("w"), j.Lit("Hello, %s!\n"), j.Id("r").Dot("PathValue").Call(j.Lit("name"))),
+	)
+
+	err = utils.SaveFile(fs, f, path+"/"+ns.Package+"/router.go")
+	if err != nil {
+		return &domain.GenerateError{Module: flv.name, File: ns.Package + "/router.go", Err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,723 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	. "github.com/dave/jennifer/jen"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+
+	"github.com/mahcks/gowizard/pkg/domain"
+	"github.com/mahcks/gowizard/pkg/manifest"
+)
+
+// LoadProject - Loads the settings of a project that was already generated by gowizard
+// The settings are taken from the manifest, projects without one fall back to go.mod and the pkg folders
+func (gen *Generator) LoadProject(projectPath string) error {
+	m, err := manifest.Load(gen.fs, projectPath)
+	if err == nil {
+		settings := m.Settings
+		settings.Path = projectPath
+		if settings.Services == nil {
+			settings.Services = map[string]string{}
+		}
+
+		gen.settings = &settings
+		gen.manifest = m
+		return nil
+	}
+
+	if !errors.Is(err, manifest.ErrNotFound) {
+		return err
+	}
+
+	file, err := gen.fs.Open(path.Join(projectPath, "go.mod"))
+	if err != nil {
+		return fmt.Errorf("unable to open go.mod, is %s a generated project? %s", projectPath, err)
+	}
+	defer file.Close()
+
+	settings := &domain.Settings{
+		Path:     projectPath,
+		Services: map[string]string{},
+	}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, "module ") {
+			settings.Module = strings.TrimSpace(strings.TrimPrefix(line, "module "))
+		}
+
+		if strings.HasPrefix(line, "go ") {
+			settings.ModuleVersion = strings.TrimSpace(strings.TrimPrefix(line, "go "))
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if settings.Module == "" {
+		return errors.New("no module name found in go.mod")
+	}
+
+	// Adapters each live in their own pkg folder
+	for name := range gen.adapters {
+		if _, err := gen.fs.Stat(path.Join(projectPath, "pkg", name)); err == nil {
+			settings.Adapters = append(settings.Adapters, name)
+		}
+	}
+	sort.Strings(settings.Adapters)
+
+	gen.settings = settings
+
+	return nil
+}
+
+// AddAdapter - Adds an adapter to a project that was already generated
+// Only internal/app/app.go, config/config.go, the config YAML files and the adapters own pkg folder are touched
+func (gen *Generator) AddAdapter(ctx context.Context, name string) error {
+	adapter, ok := gen.adapters[name]
+	if !ok {
+		return fmt.Errorf("unknown adapter: %s", name)
+	}
+
+	if gen.settings.IsAdapterChecked(name) {
+		return fmt.Errorf("adapter %s has already been added to the project", name)
+	}
+
+	settings := *gen.settings
+	settings.Adapters = append(append([]string{}, gen.settings.Adapters...), name)
+	ns, err := gen.addedNamespace(&settings, gen.adapterModule(name))
+	if err != nil {
+		return err
+	}
+
+	before, err := manifest.HashFiles(gen.fs, gen.settings.Path)
+	if err != nil {
+		return err
+	}
+
+	err = gen.patchAppFile(adapter.AppInit(ns), adapter.AppSelect(ns), adapter.AppShutdown(ns), nil, false)
+	if err != nil {
+		return err
+	}
+	gen.successMessage("Patched app.go file")
+
+	err = gen.patchConfigFiles(adapter.ConfigGo(), adapter.ConfigYAML())
+	if err != nil {
+		return err
+	}
+	gen.successMessage("Patched config files")
+
+	err = gen.fs.MkdirAll(path.Join(gen.settings.Path, ns.Package), 0755)
+	if err != nil {
+		return fmt.Errorf("error creating folder: %s", err)
+	}
+
+	err = adapter.Service(gen.fs, ns, gen.settings.Path)
+	if err != nil {
+		return err
+	}
+
+	gen.settings.Adapters = append(gen.settings.Adapters, name)
+	gen.successMessage(fmt.Sprintf("Generated %s", ns.Package))
+
+	if !gen.dryRun {
+		err = gen.executeCommand(ctx, "go mod tidy", "go.mod", "go.sum")
+		if err != nil {
+			return err
+		}
+		gen.successMessage("Executed `go mod tidy`")
+	}
+
+	return gen.updateManifest(before)
+}
+
+// AddService - Adds a service with the given flavor to a project that was already generated
+// Only internal/app/app.go, config/config.go, the config YAML files and the services own pkg folder are touched
+func (gen *Generator) AddService(ctx context.Context, service, flavor string) error {
+	svc, ok := gen.services[service]
+	if !ok {
+		return fmt.Errorf("unknown service: %s", service)
+	}
+
+	flv := svc.GetFlavor(flavor)
+	if flv == nil {
+		return fmt.Errorf("unknown flavor %s for service %s", flavor, service)
+	}
+
+	if gen.settings.IsServiceChecked(service) {
+		return fmt.Errorf("service %s has already been added to the project", service)
+	}
+
+	settings := *gen.settings
+	settings.Services = map[string]string{service: flavor}
+	for name, flavor := range gen.settings.Services {
+		settings.Services[name] = flavor
+	}
+	m, _ := gen.flavorModule(service, flavor)
+	ns, err := gen.addedNamespace(&settings, m)
+	if err != nil {
+		return err
+	}
+
+	before, err := manifest.HashFiles(gen.fs, gen.settings.Path)
+	if err != nil {
+		return err
+	}
+
+	// Services are shut down before the adapters they might use, just like in a generated project
+	var adapterShutdown []Code
+	for _, m := range gen.modules(gen.settings) {
+		if m.service == "" {
+			adapterShutdown = append(adapterShutdown, m.AppShutdown(m.ns)...)
+		}
+	}
+
+	err = gen.patchAppFile(flv.AppInit(ns), flv.AppSelect(ns), flv.AppShutdown(ns), adapterShutdown, usesErr(flv, ns))
+	if err != nil {
+		return err
+	}
+	gen.successMessage("Patched app.go file")
+
+	err = gen.patchConfigFiles(flv.ConfigGo(), flv.ConfigYAML())
+	if err != nil {
+		return err
+	}
+	gen.successMessage("Patched config files")
+
+	err = flv.Service(gen.fs, ns, gen.settings.Path)
+	if err != nil {
+		return err
+	}
+
+	gen.settings.Services[service] = flavor
+	gen.successMessage(fmt.Sprintf("Generated %s service using %s", service, flavor))
+
+	if !gen.dryRun {
+		err = gen.executeCommand(ctx, "go mod tidy", "go.mod", "go.sum")
+		if err != nil {
+			return err
+		}
+		gen.successMessage("Executed `go mod tidy`")
+
+		m.ns = ns
+		err = gen.runCommands(ctx, []module{m})
+		if err != nil {
+			return err
+		}
+	}
+
+	return gen.updateManifest(before)
+}
+
+// addedNamespace allocates the namespace of a module that is added to the project and checks the constraints with it
+// The modules of the project are allocated first, so they keep their namespaces
+func (gen *Generator) addedNamespace(settings *domain.Settings, m module) (domain.Namespace, error) {
+	modules := append(gen.modules(gen.settings), m)
+	allocate(settings, modules)
+
+	err := gen.checkConstraints(settings, modules)
+	if err != nil {
+		return domain.Namespace{}, err
+	}
+
+	return modules[len(modules)-1].ns, nil
+}
+
+// updateManifest records the settings and every file that changed since before in the manifest of a loaded project
+// Files that were changed by hand before keep their record, so upgrade still merges the changes instead of overwriting them
+// The record of a patched file is the patch applied to its base, what the generator would have written without the changes
+func (gen *Generator) updateManifest(before map[string]string) error {
+	if gen.manifest == nil {
+		return nil
+	}
+
+	after, err := manifest.HashFiles(gen.fs, gen.settings.Path)
+	if err != nil {
+		return err
+	}
+
+	gen.manifest.Settings = *gen.settings
+
+	for file, hash := range after {
+		if before[file] == hash {
+			continue
+		}
+
+		generated := gen.patched[file]
+		if generated == nil {
+			// New files and files nobody touched are recorded as they are now
+			if recorded, existed := before[file]; existed && recorded != gen.manifest.Files[file] {
+				continue
+			}
+
+			generated, err = afero.ReadFile(gen.fs, path.Join(gen.settings.Path, file))
+			if err != nil {
+				return err
+			}
+		}
+
+		gen.manifest.Files[file] = manifest.Hash(generated)
+
+		if gen.manifest.Template == nil {
+			err = manifest.WriteBase(gen.fs, gen.settings.Path, file, generated)
+			if err != nil {
+				return fmt.Errorf("error writing base of %s: %s", file, err)
+			}
+		}
+	}
+	gen.patched = nil
+
+	err = gen.manifest.Save(gen.fs, gen.settings.Path)
+	if err != nil {
+		return fmt.Errorf("error writing %s: %s", manifest.Filename, err)
+	}
+	gen.successMessage(fmt.Sprintf("Updated %s", manifest.Filename))
+
+	return nil
+}
+
+// patchFile applies a patch to a file of the project, name is relative to the project
+// In a loaded project the patch is applied to the base of the file as well, see updateManifest
+func (gen *Generator) patchFile(name string, patch func(src []byte) ([]byte, error)) error {
+	filename := path.Join(gen.settings.Path, name)
+
+	info, err := gen.fs.Stat(filename)
+	if err != nil {
+		return err
+	}
+
+	src, err := afero.ReadFile(gen.fs, filename)
+	if err != nil {
+		return err
+	}
+
+	patched, err := patch(src)
+	if err != nil {
+		return err
+	}
+
+	err = afero.WriteFile(gen.fs, filename, patched, info.Mode().Perm())
+	if err != nil {
+		return fmt.Errorf("error writing %s: %s", filename, err)
+	}
+
+	if gen.manifest == nil {
+		return nil
+	}
+
+	if gen.patched == nil {
+		gen.patched = map[string][]byte{}
+	}
+	gen.patched[name] = nil
+
+	// Without a base, or when the patch doesn't apply to it, the file is treated like one that was changed by a command
+	base, err := afero.ReadFile(gen.fs, manifest.BasePath(gen.settings.Path, name))
+	if err != nil {
+		return nil
+	}
+
+	patchedBase, err := patch(base)
+	if err == nil {
+		gen.patched[name] = patchedBase
+	}
+
+	return nil
+}
+
+// patchAppFile inserts the init, select and shutdown code of a module into the Run function of internal/app/app.go
+// The shutdown code goes in front of the first statement of shutdownBefore that's in Run, or at the end of Run
+func (gen *Generator) patchAppFile(init []Code, selectBranch Code, shutdown []Code, shutdownBefore []Code, needsErr bool) error {
+	filename := path.Join(gen.settings.Path, "internal/app/app.go")
+
+	// Render the snippets in a function that is shaped like Run so the code can be cut back out
+	f := NewFilePathName(gen.settings.Module+"/internal/app", "app")
+	f.Func().Id("Run").Params().BlockFunc(func(g *Group) {
+		g.Add(init...)
+		g.Select().Block(selectBranch)
+		g.Add(shutdown...)
+	})
+
+	snippet, snippetFset, snippetFile, err := renderAndParse(f)
+	if err != nil {
+		return err
+	}
+
+	offset := func(pos token.Pos) int {
+		return snippetFset.Position(pos).Offset
+	}
+
+	snippetRun := findFunc(snippetFile, "Run")
+	snippetSelect := findSelect(snippetRun)
+
+	initCode := snippet[offset(snippetRun.Body.Lbrace)+1 : offset(snippetSelect.Pos())]
+	selectCode := snippet[offset(snippetSelect.Body.Lbrace)+1 : offset(snippetSelect.Body.Rbrace)]
+	shutdownCode := snippet[offset(snippetSelect.End()):offset(snippetRun.Body.Rbrace)]
+
+	// The statements are rendered on their own, their imports are already in app.go
+	anchorFile := NewFilePathName(gen.settings.Module+"/internal/app", "app")
+	anchorFile.Func().Id("Run").Params().Block(shutdownBefore...)
+
+	_, anchorFset, anchorAST, err := renderAndParse(anchorFile)
+	if err != nil {
+		return err
+	}
+
+	anchors := map[string]bool{}
+	for _, stmt := range findFunc(anchorAST, "Run").Body.List {
+		anchors[nodeString(anchorFset, stmt)] = true
+	}
+
+	return gen.patchFile("internal/app/app.go", func(src []byte) ([]byte, error) {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", filename, err)
+		}
+
+		run := findFunc(file, "Run")
+		if run == nil {
+			return nil, fmt.Errorf("no Run function found in %s", filename)
+		}
+
+		sel := findSelect(run)
+		if sel == nil {
+			return nil, fmt.Errorf("no select statement found in the Run function of %s", filename)
+		}
+
+		if strings.TrimSpace(string(initCode)) != "" && bytes.Contains(src, bytes.TrimSpace(initCode)) {
+			return nil, errors.New("the code for this module is already in internal/app/app.go")
+		}
+
+		// Init code goes right before the interrupt listener
+		initOffset := fset.Position(sel.Pos()).Offset
+		for _, stmt := range run.Body.List {
+			if assign, ok := stmt.(*ast.AssignStmt); ok && isIdent(assign.Lhs[0], "interrupt") {
+				initOffset = fset.Position(stmt.Pos()).Offset
+				break
+			}
+		}
+
+		for _, group := range file.Comments {
+			if group.Pos() > run.Body.Lbrace && group.End() < sel.Pos() && strings.Contains(group.Text(), "Listen for interuptions") {
+				initOffset = fset.Position(group.Pos()).Offset
+				break
+			}
+		}
+
+		shutdownOffset := fset.Position(run.Body.Rbrace).Offset
+		for _, stmt := range run.Body.List {
+			if stmt.Pos() > sel.End() && anchors[nodeString(fset, stmt)] {
+				shutdownOffset = lineStart(src, fset.Position(stmt.Pos()).Offset)
+				break
+			}
+		}
+
+		edits := []edit{
+			{offset: initOffset, text: string(initCode) + "\n"},
+			{offset: fset.Position(sel.Body.Rbrace).Offset, text: string(selectCode) + "\n"},
+			{offset: shutdownOffset, text: string(shutdownCode) + "\n"},
+		}
+
+		if needsErr && !declaresErr(run) {
+			edits = append(edits, edit{offset: fset.Position(run.Body.Lbrace).Offset + 1, text: "\nvar err error\n"})
+		}
+
+		imports, err := importEdits(fset, file, snippetFile)
+		if err != nil {
+			return nil, err
+		}
+		edits = append(edits, imports...)
+
+		return formatGo(filename, applyEdits(src, edits))
+	})
+}
+
+// patchConfigFiles adds the config of a module to config/config.go and the config YAML files
+func (gen *Generator) patchConfigFiles(configGo *Statement, configYAML map[string]interface{}) error {
+	if configGo != nil {
+		filename := path.Join(gen.settings.Path, "config/config.go")
+
+		f := NewFilePathName(gen.settings.Module+"/config", "config")
+		f.Type().Id("Config").Struct(configGo)
+
+		snippet, snippetFset, snippetFile, err := renderAndParse(f)
+		if err != nil {
+			return err
+		}
+
+		snippetStruct := findStruct(snippetFile, "Config")
+		fieldCode := snippet[snippetFset.Position(snippetStruct.Fields.Opening).Offset+1 : snippetFset.Position(snippetStruct.Fields.Closing).Offset]
+
+		err = gen.patchFile("config/config.go", func(src []byte) ([]byte, error) {
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing %s: %s", filename, err)
+			}
+
+			config := findStruct(file, "Config")
+			if config == nil {
+				return nil, fmt.Errorf("no Config struct found in %s", filename)
+			}
+
+			edits := []edit{{offset: fset.Position(config.Fields.Closing).Offset, text: string(fieldCode) + "\n"}}
+
+			imports, err := importEdits(fset, file, snippetFile)
+			if err != nil {
+				return nil, err
+			}
+			edits = append(edits, imports...)
+
+			return formatGo(filename, applyEdits(src, edits))
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if configYAML == nil {
+		return nil
+	}
+
+	doc, err := yaml.Marshal(configYAML)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range []string{"config/config.yaml", "config/config.dev.yaml"} {
+		filename := path.Join(gen.settings.Path, name)
+
+		err = gen.patchFile(name, func(b []byte) ([]byte, error) {
+			existing := map[string]interface{}{}
+			err := yaml.Unmarshal(b, &existing)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing %s: %s", filename, err)
+			}
+
+			for key := range configYAML {
+				if _, ok := existing[key]; ok {
+					return nil, fmt.Errorf("%s already has a %s section", name, key)
+				}
+			}
+
+			patched := append([]byte{}, b...)
+			if len(patched) != 0 && !bytes.HasSuffix(patched, []byte("\n")) {
+				patched = append(patched, '\n')
+			}
+			patched = append(patched, '\n')
+
+			return append(patched, doc...), nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// edit is an insertion of text at a byte offset of a file
+type edit struct {
+	offset int
+	text   string
+}
+
+// applyEdits applies the insertions from the back of the file to the front so the offsets stay valid
+func applyEdits(src []byte, edits []edit) []byte {
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].offset > edits[j].offset
+	})
+
+	out := append([]byte{}, src...)
+	for _, e := range edits {
+		out = append(out[:e.offset], append([]byte(e.text), out[e.offset:]...)...)
+	}
+
+	return out
+}
+
+// importEdits returns the insertions needed for file to import everything snippet imports
+func importEdits(fset *token.FileSet, file, snippet *ast.File) ([]edit, error) {
+	existing := make(map[string]string, len(file.Imports))
+	for _, spec := range file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		existing[importPath] = importName(spec, importPath)
+	}
+
+	var lines []string
+	for _, spec := range snippet.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		name := importName(spec, importPath)
+
+		if existingName, ok := existing[importPath]; ok {
+			if existingName != name {
+				return nil, fmt.Errorf("%s is already imported as %s", importPath, existingName)
+			}
+			continue
+		}
+
+		if spec.Name != nil {
+			lines = append(lines, spec.Name.Name+" "+spec.Path.Value)
+		} else {
+			lines = append(lines, spec.Path.Value)
+		}
+	}
+
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	// Add to the existing import block or create a new one after the package clause
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+
+		if gen.Rparen.IsValid() {
+			return []edit{{offset: fset.Position(gen.Rparen).Offset, text: strings.Join(lines, "\n") + "\n"}}, nil
+		}
+
+		return []edit{{offset: fset.Position(gen.End()).Offset, text: "\nimport (\n" + strings.Join(lines, "\n") + "\n)\n"}}, nil
+	}
+
+	return []edit{{offset: fset.Position(file.Name.End()).Offset, text: "\n\nimport (\n" + strings.Join(lines, "\n") + "\n)\n"}}, nil
+}
+
+// importName returns the name a package is referred to by in a file
+func importName(spec *ast.ImportSpec, importPath string) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+
+	return path.Base(importPath)
+}
+
+// renderAndParse renders a jennifer file and parses the result
+func renderAndParse(f *File) ([]byte, *token.FileSet, *ast.File, error) {
+	buf := &bytes.Buffer{}
+	err := f.Render(buf)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error rendering code: %s", err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", buf.Bytes(), parser.ParseComments)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error parsing rendered code: %s", err)
+	}
+
+	return buf.Bytes(), fset, file, nil
+}
+
+// nodeString prints a node without its comments, so the same code compares equal no matter where it was parsed
+func nodeString(fset *token.FileSet, node ast.Node) string {
+	var buf bytes.Buffer
+	_ = printer.Fprint(&buf, fset, node)
+	return buf.String()
+}
+
+// lineStart returns the offset of the start of the line the offset is on
+func lineStart(src []byte, offset int) int {
+	return bytes.LastIndexByte(src[:offset], '\n') + 1
+}
+
+// formatGo formats the patched Go source code of a file
+func formatGo(filename string, src []byte) ([]byte, error) {
+	formatted, err := format.Source(src)
+	if err != nil {
+		return nil, fmt.Errorf("error formatting %s: %s", filename, err)
+	}
+
+	return formatted, nil
+}
+
+func findFunc(file *ast.File, name string) *ast.FuncDecl {
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == name {
+			return fn
+		}
+	}
+
+	return nil
+}
+
+func findStruct(file *ast.File, name string) *ast.StructType {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			if st, ok := typeSpec.Type.(*ast.StructType); ok && typeSpec.Name.Name == name {
+				return st
+			}
+		}
+	}
+
+	return nil
+}
+
+func findSelect(fn *ast.FuncDecl) *ast.SelectStmt {
+	if fn == nil || fn.Body == nil {
+		return nil
+	}
+
+	for _, stmt := range fn.Body.List {
+		if sel, ok := stmt.(*ast.SelectStmt); ok {
+			return sel
+		}
+	}
+
+	return nil
+}
+
+// declaresErr checks if a function body declares err with `var err error`
+func declaresErr(fn *ast.FuncDecl) bool {
+	for _, stmt := range fn.Body.List {
+		decl, ok := stmt.(*ast.DeclStmt)
+		if !ok {
+			continue
+		}
+
+		gen, ok := decl.Decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			for _, name := range spec.(*ast.ValueSpec).Names {
+				if name.Name == "err" {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func isIdent(expr ast.Expr, name string) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == name
+}
@@ -0,0 +1,195 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+
+	"github.com/mahcks/gowizard/pkg/manifest"
+)
+
+// generateProject generates a dry run of a redis and gin project to /project, the options replace the adapters and services
+func generateProject(t *testing.T, fs afero.Fs, opts ...Option) {
+	t.Helper()
+
+	gen := NewGenerator(append([]Option{
+		WithModule("github.com/gowizard/add"),
+		WithGoVersion("1.20"),
+		WithPath("/project"),
+		WithAdapters("redis"),
+		WithServices(map[string]string{"rest": "gin"}),
+		WithFs(fs),
+		WithDryRun(true),
+	}, opts...)...)
+
+	err := gen.Generate(context.Background())
+	if err != nil {
+		t.Fatalf("error generating project: %s", err)
+	}
+}
+
+// addAdapter loads the project in /project and adds an adapter to it
+func addAdapter(t *testing.T, fs afero.Fs, name string) *manifest.Manifest {
+	t.Helper()
+
+	gen := NewGenerator(WithFs(fs), WithDryRun(true))
+
+	err := gen.LoadProject("/project")
+	if err != nil {
+		t.Fatalf("error loading project: %s", err)
+	}
+
+	err = gen.AddAdapter(context.Background(), name)
+	if err != nil {
+		t.Fatalf("error adding %s: %s", name, err)
+	}
+
+	m, err := manifest.Load(fs, "/project")
+	if err != nil {
+		t.Fatalf("error loading manifest: %s", err)
+	}
+
+	return m
+}
+
+func TestAdd(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	generateProject(t, fs, WithServices(map[string]string{}))
+
+	gen := NewGenerator(WithFs(fs), WithDryRun(true))
+	err := gen.LoadProject("/project")
+	if err != nil {
+		t.Fatalf("error loading project: %s", err)
+	}
+
+	err = gen.AddAdapter(context.Background(), "postgres")
+	if err != nil {
+		t.Fatalf("error adding postgres: %s", err)
+	}
+
+	err = gen.AddService(context.Background(), "rest", "gin")
+	if err != nil {
+		t.Fatalf("error adding rest: %s", err)
+	}
+
+	for _, err := range []error{
+		gen.AddAdapter(context.Background(), "redis"),
+		gen.AddAdapter(context.Background(), "cassandra"),
+		gen.AddService(context.Background(), "rest", "echo"),
+		gen.AddService(context.Background(), "gql", "graphql-go"),
+	} {
+		if err == nil {
+			t.Errorf("expected adding an existing or unknown module to fail")
+		}
+	}
+
+	m, err := manifest.Load(fs, "/project")
+	if err != nil {
+		t.Fatalf("error loading manifest: %s", err)
+	}
+	if strings.Join(m.Settings.Adapters, ",") != "redis,postgres" || m.Settings.Services["rest"] != "gin" {
+		t.Errorf("expected the settings to have the added modules, got %+v", m.Settings)
+	}
+
+	app, err := afero.ReadFile(fs, "/project/internal/app/app.go")
+	if err != nil {
+		t.Fatalf("error reading app.go: %s", err)
+	}
+
+	// Services are shut down before the adapters they might use
+	order := []string{"cancel()", "restServer.Shutdown()", "redisClient.Close()", "postgresPool.Close()"}
+	last := -1
+	for _, stmt := range order {
+		i := bytes.Index(app, []byte(stmt))
+		if i <= last {
+			t.Fatalf("expected the shutdown to be in the order %q:\n%s", order, app)
+		}
+		last = i
+	}
+
+	if testing.Short() {
+		return
+	}
+
+	compileErrs, err := gen.Verify()
+	if err != nil {
+		t.Fatalf("error verifying project: %s", err)
+	}
+
+	for _, compileErr := range compileErrs {
+		t.Errorf("project doesn't compile after adding modules: %s", compileErr)
+	}
+}
+
+func TestAddKeepsLocalChanges(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	generateProject(t, fs)
+
+	const file = "internal/app/app.go"
+	const local = "// Changed by hand\n"
+
+	src, err := afero.ReadFile(fs, "/project/"+file)
+	if err != nil {
+		t.Fatalf("error reading %s: %s", file, err)
+	}
+	err = afero.WriteFile(fs, "/project/"+file, append(src, local...), 0644)
+	if err != nil {
+		t.Fatalf("error writing %s: %s", file, err)
+	}
+
+	m := addAdapter(t, fs, "mariadb")
+
+	ours, err := afero.ReadFile(fs, "/project/"+file)
+	if err != nil {
+		t.Fatalf("error reading %s: %s", file, err)
+	}
+	if !bytes.Contains(ours, []byte(local)) || !bytes.Contains(ours, []byte("mariadb.New")) {
+		t.Fatalf("expected %s to have the local change and the adapter:\n%s", file, ours)
+	}
+
+	base, err := afero.ReadFile(fs, manifest.BasePath("/project", file))
+	if err != nil {
+		t.Fatalf("error reading base of %s: %s", file, err)
+	}
+	if bytes.Contains(base, []byte(local)) {
+		t.Errorf("expected the base of %s not to have the local change:\n%s", file, base)
+	}
+	if !bytes.Contains(base, []byte("mariadb.New")) {
+		t.Errorf("expected the base of %s to be patched with the adapter:\n%s", file, base)
+	}
+	if m.Files[file] != manifest.Hash(base) {
+		t.Errorf("expected %s to be recorded with the hash of its base", file)
+	}
+	if m.Files[file] == manifest.Hash(ours) {
+		t.Errorf("expected %s to still be recorded as changed locally", file)
+	}
+}
+
+func TestAddRecordsUntouchedFiles(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	generateProject(t, fs)
+
+	m := addAdapter(t, fs, "mariadb")
+
+	for _, file := range []string{"internal/app/app.go", "config/config.go", "config/config.yaml", "pkg/mariadb/adapter.go"} {
+		ours, err := afero.ReadFile(fs, "/project/"+file)
+		if err != nil {
+			t.Fatalf("error reading %s: %s", file, err)
+		}
+
+		base, err := afero.ReadFile(fs, manifest.BasePath("/project", file))
+		if err != nil {
+			t.Fatalf("error reading base of %s: %s", file, err)
+		}
+
+		if !bytes.Equal(ours, base) {
+			t.Errorf("expected the base of %s to be the file", file)
+		}
+		if m.Files[file] != manifest.Hash(ours) {
+			t.Errorf("expected %s to be recorded with its hash", file)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mahcks/gowizard/pkg/domain"
+)
+
+// ConstraintError explains why the enabled adapters and services can't be generated together
+type ConstraintError struct {
+	Module string // Name of the adapter, or service/flavor for flavors
+	Reason string
+}
+
+func (e *ConstraintError) Error() string {
+	return fmt.Sprintf("%s %s", e.Module, e.Reason)
+}
+
+// ResolveRequirements - Enables the adapters that are required by the enabled adapters and flavors
+// The added adapters are returned and reported as events, i.e. the wizard auto-selects dependencies with it
+func (gen *Generator) ResolveRequirements() []string {
+	var added []string
+
+	// Required adapters can require adapters themselves, so repeat until nothing changes
+	for changed := true; changed; {
+		changed = false
+
+		for _, m := range gen.modules(gen.settings) {
+			// The adapter of the migrations is chosen explicitly, so it's rejected instead of added when it's missing
+			if m.name == "migrate" {
+				continue
+			}
+
+			for _, required := range m.Constraints().Requires {
+				if gen.settings.IsAdapterChecked(required) {
+					continue
+				}
+
+				if _, ok := gen.adapters[required]; !ok {
+					continue
+				}
+
+				gen.settings.Adapters = append(gen.settings.Adapters, required)
+				added = append(added, required)
+				changed = true
+
+				gen.emit(EventStep, fmt.Sprintf("Added adapter %s, it's required by %s", required, m.name))
+			}
+		}
+	}
+
+	return added
+}
+
+// CheckConstraints - Returns every constraint the enabled adapters and services violate, each one is a *ConstraintError
+func (gen *Generator) CheckConstraints() error {
+	return gen.checkConstraints(gen.settings, gen.modules(gen.settings))
+}
+
+// checkConstraints checks the constraints of the modules, the first package of each one is the allocated package of its namespace
+func (gen *Generator) checkConstraints(settings *domain.Settings, modules []module) error {
+	var errs []error
+
+	owners := make(map[string]string)  // key is the package, value the module that generates it
+	conflicts := make(map[string]bool) // a conflict that both modules declare is reported once
+
+	for _, m := range modules {
+		constraints := m.Constraints()
+
+		for _, required := range constraints.Requires {
+			if !settings.IsAdapterChecked(required) {
+				errs = append(errs, &ConstraintError{Module: m.name, Reason: fmt.Sprintf("requires the %s adapter, add it as well", required)})
+			}
+		}
+
+		for _, conflict := range constraints.Conflicts {
+			if !settings.IsAdapterChecked(conflict) || conflicts[conflict+" "+m.name] {
+				continue
+			}
+
+			conflicts[m.name+" "+conflict] = true
+			errs = append(errs, &ConstraintError{Module: m.name, Reason: fmt.Sprintf("conflicts with the %s adapter, only one of them can be used", conflict)})
+		}
+
+		if constraints.GoVersion != "" && settings.ModuleVersion != "" && versionLess(settings.ModuleVersion, constraints.GoVersion) {
+			errs = append(errs, &ConstraintError{Module: m.name, Reason: fmt.Sprintf("requires Go %s or newer, the module uses Go %s", constraints.GoVersion, settings.ModuleVersion)})
+		}
+
+		packages := []string{m.ns.Package}
+		if len(constraints.Packages) > 1 {
+			packages = append(packages, constraints.Packages[1:]...)
+		}
+
+		for _, pkg := range packages {
+			if owner, ok := owners[pkg]; ok {
+				errs = append(errs, &ConstraintError{Module: m.name, Reason: fmt.Sprintf("generates %s just like %s, only one of them can be used", pkg, owner)})
+				continue
+			}
+
+			owners[pkg] = m.name
+		}
+	}
+
+	// The migrate module requires its adapter, an adapter without migrations has no module to check
+	if _, ok := gen.migrateModule(settings); settings.Migrations != "" && !ok {
+		names := gen.migrationAdapters()
+		if len(names) > 1 {
+			names = []string{strings.Join(names[:len(names)-1], ", "), names[len(names)-1]}
+		}
+
+		errs = append(errs, &ConstraintError{Module: "migrate", Reason: fmt.Sprintf("can't scaffold migrations for %s, use the %s adapter", settings.Migrations, strings.Join(names, " or "))})
+	}
+
+	return errors.Join(errs...)
+}
+
+// versionLess compares Go versions like 1.20 and 1.21.3, a go prefix is ignored
+func versionLess(a, b string) bool {
+	as := strings.Split(strings.TrimPrefix(a, "go"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "go"), ".")
+
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+
+		if x != y {
+			return x < y
+		}
+	}
+
+	return false
+}
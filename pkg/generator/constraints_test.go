@@ -0,0 +1,128 @@
+package generator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mahcks/gowizard/pkg/domain"
+)
+
+// constrainedAdapter is an adapter that only has a name and constraints
+type constrainedAdapter struct {
+	domain.ModuleI
+	name        string
+	constraints domain.Constraints
+}
+
+func (a *constrainedAdapter) GetName() string                 { return a.name }
+func (a *constrainedAdapter) Constraints() domain.Constraints { return a.constraints }
+
+func TestCheckConstraints(t *testing.T) {
+	gen := NewGenerator(WithGoVersion("1.20"))
+	gen.adapters["queue"] = &constrainedAdapter{name: "queue", constraints: domain.Constraints{
+		Requires:  []string{"redis"},
+		GoVersion: "1.21",
+		Packages:  []string{"pkg/queue", "pkg/redis"},
+	}}
+	gen.adapters["memcached"] = &constrainedAdapter{name: "memcached", constraints: domain.Constraints{
+		Conflicts: []string{"redis"},
+		Packages:  []string{"pkg/memcached"},
+	}}
+
+	// The built-in modules get their own packages, so only the Go version and the migrations constrain them
+	cases := []struct {
+		name       string
+		adapters   []string
+		services   map[string]string
+		logger     string
+		migrations string
+		expected   []string
+	}{
+		{"valid", []string{"mariadb", "redis"}, nil, "", "", nil},
+		{"mysql drivers", []string{"mariadb", "sql"}, nil, "", "", nil},
+		{"servers", nil, map[string]string{"rest": "gin", "gql": "gqlgen", "grpc": "grpc-go"}, "zap", "", nil},
+		{"go version", []string{"redis"}, nil, "slog", "", []string{"slog requires Go 1.21 or newer, the module uses Go 1.20"}},
+		{"conflict", []string{"memcached", "redis"}, nil, "", "", []string{"memcached conflicts with the redis adapter"}},
+		{"requires", []string{"queue"}, nil, "", "", []string{"queue requires the redis adapter", "queue requires Go 1.21 or newer"}},
+		{"package", []string{"queue", "redis"}, nil, "", "", []string{"queue requires Go 1.21 or newer", "redis generates pkg/redis just like queue"}},
+		{"migrations", []string{"postgres", "mariadb"}, nil, "", "mariadb", nil},
+		{"migrations without their adapter", []string{"postgres"}, nil, "", "mariadb", []string{"migrate requires the mariadb adapter"}},
+		{"migrations without database", []string{"redis"}, nil, "", "redis", []string{"migrate can't scaffold migrations for redis, use the mariadb, postgres or sql adapter"}},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			gen.Configure(WithAdapters(tc.adapters...), WithServices(tc.services), WithLogger(tc.logger), WithMigrations(tc.migrations))
+
+			err := gen.CheckConstraints()
+			if len(tc.expected) == 0 {
+				if err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+				return
+			}
+
+			var constraintErr *ConstraintError
+			if !errors.As(err, &constraintErr) {
+				t.Fatalf("expected a *ConstraintError, got %v", err)
+			}
+
+			for _, msg := range tc.expected {
+				if !strings.Contains(err.Error(), msg) {
+					t.Errorf("expected %q in %q", msg, err)
+				}
+			}
+
+			if strings.Count(err.Error(), "\n")+1 != len(tc.expected) {
+				t.Errorf("expected %d errors, got %q", len(tc.expected), err)
+			}
+		})
+	}
+}
+
+func TestResolveRequirements(t *testing.T) {
+	gen := NewGenerator(WithAdapters("queue"), WithMigrations("postgres"))
+	gen.adapters["queue"] = &constrainedAdapter{name: "queue", constraints: domain.Constraints{Requires: []string{"worker"}}}
+	gen.adapters["worker"] = &constrainedAdapter{name: "worker", constraints: domain.Constraints{Requires: []string{"redis"}}}
+
+	added := gen.ResolveRequirements()
+	if strings.Join(added, ",") != "worker,redis" {
+		t.Fatalf("expected worker and redis to be added, got %v", added)
+	}
+
+	// The migrations don't add their adapter, they name the wrong one when it isn't enabled
+	err := gen.CheckConstraints()
+	if err == nil || err.Error() != "migrate requires the postgres adapter, add it as well" {
+		t.Fatalf("expected the migrations to need postgres, got %v", err)
+	}
+}
+
+func TestAllocateNamespaces(t *testing.T) {
+	gen := NewGenerator(WithModule("github.com/gowizard/namespaces"), WithAdapters("cache", "redis"))
+	gen.adapters["cache"] = &constrainedAdapter{name: "cache", constraints: domain.Constraints{Packages: []string{"pkg/redis"}}}
+
+	modules := gen.modules(gen.settings)
+	if len(modules) != 2 {
+		t.Fatalf("expected 2 modules, got %d", len(modules))
+	}
+
+	// cache comes first, so redis gets a numbered package
+	if modules[0].ns.Package != "pkg/redis" || modules[1].ns.Package != "pkg/redis2" {
+		t.Errorf("expected pkg/redis and pkg/redis2, got %s and %s", modules[0].ns.Package, modules[1].ns.Package)
+	}
+
+	if modules[1].ns.Path() != "github.com/gowizard/namespaces/pkg/redis2" || modules[1].ns.PackageName() != "redis2" {
+		t.Errorf("unexpected package of redis: %s", modules[1].ns.Path())
+	}
+
+	if modules[0].ns.Id("client") == modules[1].ns.Id("client") {
+		t.Errorf("expected unique identifiers, got %s twice", modules[0].ns.Id("client"))
+	}
+
+	err := gen.CheckConstraints()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
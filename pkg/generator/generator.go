@@ -2,92 +2,101 @@ package generator
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
-	"os/exec"
 	"path"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 
 	. "github.com/dave/jennifer/jen"
-	"github.com/mgutz/ansi"
+	"github.com/spf13/afero"
 	"gopkg.in/yaml.v2"
 
-	adapterTemplates "github.com/mahcks/gowizard/pkg/adapters"
 	"github.com/mahcks/gowizard/pkg/domain"
-	serviceTemplates "github.com/mahcks/gowizard/pkg/services"
-	repoTemplates "github.com/mahcks/gowizard/pkg/templates"
+	"github.com/mahcks/gowizard/pkg/journal"
+	"github.com/mahcks/gowizard/pkg/manifest"
+	"github.com/mahcks/gowizard/pkg/registry"
 	"github.com/mahcks/gowizard/pkg/utils"
+	"github.com/mahcks/gowizard/pkg/verify"
 )
 
 type Generator struct {
+	version     string        // version of gowizard, recorded in the manifest
+	events      EventHandler  // receives progress events, nil drops them
+	runner      CommandRunner // executes the go and git commands
+	dryRun      bool          // don't run the go tool, the project is only written to the filesystem
+	fs          afero.Fs      // filesystem the project is written to, it's wrapped by the journal
+	journal     *journal.Fs   // records every change to the filesystem so it can be rolled back
 	settings    *domain.Settings
-	useTemplate bool // use a template for the module instead of generating from scratch
-	directories map[string][]string
+	manifest    *manifest.Manifest // manifest of a project loaded with LoadProject
+	patched     map[string][]byte  // files changed by add, the value is the patched base or nil when there is none
+	useTemplate bool               // use a template for the module instead of generating from scratch
+	files       map[string][]byte  // files written to the project besides the generated ones, keyed by their path in it
 	adapters    map[string]domain.ModuleI
+	loggers     map[string]domain.ModuleI
 	controllers map[string]domain.ModuleI
 	services    map[string]domain.ServiceI
 	templates   map[string]domain.TemplateI
 }
 
-// NewGenerator - Create a new generator
-func NewGenerator() *Generator {
-	// Register adapters here
-	adapters := map[string]domain.ModuleI{
-		"mariadb":  adapterTemplates.NewMariaDBAdapter(),
-		"mongodb":  adapterTemplates.NewMongoDBAdapter(),
-		"postgres": adapterTemplates.NewPostgresAdapter(),
-		"redis":    adapterTemplates.NewRedisAdapter(),
-		"sql":      adapterTemplates.NewSQLAdapter(),
-	}
-
-	// Register services
-	services := map[string]domain.ServiceI{
-		"rest": serviceTemplates.NewRESTService(),
-		"gql":  serviceTemplates.NewGQLService(),
-	}
-
-	// Register templates here
-	templates := map[string]domain.TemplateI{
-		"github.com/amitshekhariitbhu/go-backend-clean-architecture": repoTemplates.NewGoBackendCleanArchitectureTemplateRepo(),
-		"github.com/bxcodec/go-clean-arch":                           repoTemplates.NewGoCleanArchTemplateRepo(),
-		"github.com/evrone/go-clean-template":                        repoTemplates.NewGoCleanTemplateRepo(),
-		"github.com/thangchung/go-coffeeshop":                        repoTemplates.NewGoCoffeshopRepo(),
-	}
+// NewGenerator - Create a new generator, settings and everything else are given as options
+// The adapters, services, loggers and templates are the ones in the registry at the time it's created
+func NewGenerator(opts ...Option) *Generator {
+	journalFs := journal.NewFs(afero.NewOsFs())
 
-	return &Generator{
-		adapters:  adapters,
-		templates: templates,
-		services:  services,
+	gen := &Generator{
+		fs:        journalFs,
+		journal:   journalFs,
+		runner:    runShell,
+		settings:  &domain.Settings{},
+		adapters:  registry.Adapters(),
+		loggers:   registry.Loggers(),
+		templates: registry.Templates(),
+		services:  registry.Services(),
 	}
-}
+	gen.Configure(opts...)
 
-// SetSettings - Set the settings for the generator
-func (gen *Generator) SetSettings(moduleName, moduleVersion, path string, enabledAdapters []string, enabledServices map[string]string) {
-	gen.settings = &domain.Settings{
-		Module:        moduleName,
-		ModuleVersion: moduleVersion,
-		Path:          path,
-		Adapters:      enabledAdapters,
-		Services:      enabledServices,
-	}
+	return gen
 }
 
 // UseTemplate - Use a template to generate the module
-func (gen *Generator) UseTemplate(template string, isCustom bool) error {
+func (gen *Generator) UseTemplate(ctx context.Context, template string, isCustom bool) error {
 	// Flag used to determine various edge cases
 	gen.useTemplate = true
 
-	// Clone repo to target path
-	err := gen.executeCommand(fmt.Sprintf("git clone %s .", fmt.Sprintf("https://%s.git", template)))
+	// The template is only known once it's cloned, so a dry run doesn't clone it and only reports what would happen
+	if gen.dryRun {
+		gen.successMessage(fmt.Sprintf("Would clone https://%s.git and generate %s from it in %s", template, gen.settings.Module, gen.settings.Path))
+		gen.emit(EventDone, fmt.Sprintf("Dry run of %s from %s", gen.settings.Module, template))
+		return nil
+	}
+
+	// Clone repo to a temporary directory, it's copied to the target path without the .git folder
+	tmpDir, err := os.MkdirTemp("", "gowizard-template-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	_, err = gen.runner(ctx, tmpDir, fmt.Sprintf("git clone %s .", fmt.Sprintf("https://%s.git", template)))
 	if err != nil {
 		return err
 	}
 	gen.successMessage(fmt.Sprintf("Cloned %s", template))
 
-	// Remove .git folder
-	err = os.RemoveAll(gen.settings.Path + "/.git")
+	// Remember which commit was cloned for the manifest
+	commit, err := gen.runner(ctx, tmpDir, "git rev-parse HEAD")
+	if err != nil {
+		return err
+	}
+
+	err = gen.copyTemplate(tmpDir)
 	if err != nil {
 		return err
 	}
@@ -100,7 +109,7 @@ func (gen *Generator) UseTemplate(template string, isCustom bool) error {
 
 	if !isCustom {
 		// Execute the setup code for the specific template
-		err = gen.templates[template].Setup(gen.settings.Path)
+		err = gen.templates[template].Setup(gen.fs, gen.settings.Path)
 		if err != nil {
 			return err
 		}
@@ -114,17 +123,74 @@ func (gen *Generator) UseTemplate(template string, isCustom bool) error {
 	}
 	gen.successMessage("Updated imports...")
 
-	err = gen.executeCommand("go mod tidy")
+	if !gen.dryRun {
+		err = gen.executeCommand(ctx, "go mod tidy", "go.mod", "go.sum")
+		if err != nil {
+			return err
+		}
+		gen.successMessage("Executed `go mod tidy`")
+	}
+
+	err = gen.writeManifest(&manifest.Template{
+		Name:   template,
+		Commit: commit,
+		Custom: isCustom,
+	})
 	if err != nil {
 		return err
 	}
-	gen.successMessage("Executed `go mod tidy`")
+	gen.successMessage(fmt.Sprintf("Wrote %s", manifest.Filename))
 
-	fmt.Println(ansi.Color("Done!", "green+b"), fmt.Sprintf("\033[3m%s\033[0m", utils.GetRandomPhrase()))
+	gen.emit(EventDone, fmt.Sprintf("Generated %s from %s", gen.settings.Module, template))
 
 	return nil
 }
 
+// Verify - Type-checks the generated project offline and returns its compile errors
+// Third-party packages that aren't in vendor or the module cache can't be checked, a warning names them, see verify.Check
+// In a dry run the commands of the modules haven't run, so the packages they complete are left out
+func (gen *Generator) Verify() ([]verify.Error, error) {
+	compileErrs, missing, err := verify.Check(gen.fs, gen.settings.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(missing) != 0 {
+		gen.emit(EventWarning, fmt.Sprintf("Code that uses %s is only partially checked, they aren't in vendor or the module cache, run `go mod download` to check it", strings.Join(missing, ", ")))
+	}
+
+	if !gen.dryRun {
+		return compileErrs, nil
+	}
+
+	var incomplete []string
+	for _, m := range gen.modules(gen.settings) {
+		if _, ok := m.ModuleI.(domain.CommandsI); ok {
+			incomplete = append(incomplete, m.ns.Package+"/")
+		}
+	}
+
+	var errs []verify.Error
+	for _, compileErr := range compileErrs {
+		if !hasAnyPrefix(compileErr.File, incomplete) {
+			errs = append(errs, compileErr)
+		}
+	}
+
+	return errs, nil
+}
+
+// hasAnyPrefix reports whether s starts with one of the prefixes
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // GetTemplates - Returns the templates available for the generator
 func (gen *Generator) GetTemplates() map[string]domain.TemplateI {
 	return gen.templates
@@ -140,18 +206,39 @@ func (gen *Generator) GetServices() map[string]domain.ServiceI {
 	return gen.services
 }
 
+// GetLoggers - Returns the loggers available for the generator
+func (gen *Generator) GetLoggers() map[string]domain.ModuleI {
+	return gen.loggers
+}
+
+// successMessage emits a finished step
 func (gen *Generator) successMessage(msg string) {
-	fmt.Println(ansi.Color("[✓]", "green"), ansi.Color(msg, "white"), ansi.ColorCode("reset"))
+	gen.emit(EventStep, msg)
 }
 
-func (gen *Generator) Generate() error {
-	// Genereates the folder structure
-	// Execute `go mod init <module-name>`
-	err := gen.executeCommand(fmt.Sprintf("go mod init %s", gen.settings.Module))
+// Generate - Generates the module from scratch with the enabled adapters and services
+func (gen *Generator) Generate(ctx context.Context) error {
+	err := gen.validateSettings()
 	if err != nil {
 		return err
 	}
-	gen.successMessage(fmt.Sprintf("Executed `go mod init %s`", gen.settings.Module))
+
+	// Genereates the folder structure
+	// Execute `go mod init <module-name>`
+	if gen.dryRun {
+		// The go tool can't write to the filesystem of the generator, so write what it would
+		err = afero.WriteFile(gen.fs, path.Join(gen.settings.Path, "go.mod"), []byte(fmt.Sprintf("module %s\n\ngo %s\n", gen.settings.Module, gen.settings.ModuleVersion)), 0644)
+		if err != nil {
+			return err
+		}
+		gen.successMessage("Wrote go.mod")
+	} else {
+		err = gen.executeCommand(ctx, fmt.Sprintf("go mod init %s", gen.settings.Module), "go.mod")
+		if err != nil {
+			return err
+		}
+		gen.successMessage(fmt.Sprintf("Executed `go mod init %s`", gen.settings.Module))
+	}
 
 	err = gen.setModuleVersion()
 	if err != nil {
@@ -159,70 +246,184 @@ func (gen *Generator) Generate() error {
 	}
 	gen.successMessage(fmt.Sprintf("Set module version to %s", gen.settings.ModuleVersion))
 
-	err = gen.generateFolderStructure()
+	err = gen.generateFiles(ctx)
 	if err != nil {
 		return err
 	}
-	gen.successMessage("Generated folder structure...")
 
-	// Generates the cmd/main.go file
-	err = gen.generateMainFile()
+	err = gen.writeFiles()
 	if err != nil {
 		return err
 	}
-	gen.successMessage("Generated main.go file")
 
-	// Generates the internal/app/app.go file
-	err = gen.createInternalAppFile()
-	if err != nil {
-		return err
+	if !gen.dryRun {
+		err = gen.executeCommand(ctx, "go mod tidy", "go.mod", "go.sum")
+		if err != nil {
+			return err
+		}
+		gen.successMessage("Executed `go mod tidy`")
+
+		err = gen.runCommands(ctx, gen.modules(gen.settings))
+		if err != nil {
+			return err
+		}
 	}
-	gen.successMessage("Generated app.go file")
 
-	// Generates the internal/config/config.go file
-	err = gen.createConfigGoFile()
+	err = gen.writeManifest(nil)
 	if err != nil {
 		return err
 	}
+	gen.successMessage(fmt.Sprintf("Wrote %s", manifest.Filename))
+
+	gen.emit(EventDone, fmt.Sprintf("Generated %s", gen.settings.Module))
+
+	return nil
+}
+
+// generateFiles generates every file of the project besides go.mod and go.sum
+// The context is checked between the steps, a step itself isn't interrupted
+func (gen *Generator) generateFiles(ctx context.Context) error {
+	steps := []struct {
+		run func() error
+		msg string
+	}{
+		{gen.generateFolderStructure, "Generated folder structure..."},
+		// Generates the cmd/main.go file
+		{gen.generateMainFile, "Generated main.go file"},
+		// Generates the internal/app/app.go file
+		{gen.createInternalAppFile, "Generated app.go file"},
+		// Generates the internal/config/config.go file
+		{gen.createConfigGoFile, ""},
+		{gen.createConfigYamlFile, "Generated config files"},
+		// Copies the files from the adapters folder to the project
+		{gen.copyFiles, "Copied files from adapters folder..."},
+	}
+
+	for _, step := range steps {
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
 
-	err = gen.createConfigYamlFile()
+		err = step.run()
+		if err != nil {
+			return err
+		}
+
+		if step.msg != "" {
+			gen.successMessage(step.msg)
+		}
+	}
+
+	return nil
+}
+
+// writeFiles writes the files given with WithFile to the project, sorted by name
+func (gen *Generator) writeFiles() error {
+	for _, name := range sortedKeys(gen.files) {
+		filename := filepath.Join(gen.settings.Path, filepath.FromSlash(name))
+
+		err := gen.fs.MkdirAll(filepath.Dir(filename), os.ModePerm)
+		if err != nil {
+			return err
+		}
+
+		err = afero.WriteFile(gen.fs, filename, gen.files[name], 0644)
+		if err != nil {
+			return fmt.Errorf("error writing %s: %s", name, err)
+		}
+		gen.successMessage(fmt.Sprintf("Wrote %s", name))
+	}
+
+	return nil
+}
+
+// writeManifest records the settings and a hash of every file in the project
+func (gen *Generator) writeManifest(template *manifest.Template) error {
+	files, err := manifest.HashFiles(gen.fs, gen.settings.Path)
 	if err != nil {
-		return err
+		return fmt.Errorf("error hashing files: %s", err)
+	}
+
+	m := &manifest.Manifest{
+		Version:  gen.version,
+		Settings: *gen.settings,
+		Template: template,
+		Files:    files,
 	}
-	gen.successMessage("Generated config files")
 
-	// Copies the files from the adapters folder to the project
-	err = gen.copyFiles()
+	// Keep what was generated as the base for upgrades, templates can't be upgraded so there's no need
+	if template == nil {
+		for file := range files {
+			err = gen.writeBase(file)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = m.Save(gen.fs, gen.settings.Path)
 	if err != nil {
-		return err
+		return fmt.Errorf("error writing %s: %s", manifest.Filename, err)
 	}
-	gen.successMessage("Copied files from adapters folder...")
 
-	err = gen.executeCommand("go mod tidy")
+	return nil
+}
+
+// writeBase copies a file of the project to the base of the manifest
+func (gen *Generator) writeBase(file string) error {
+	b, err := afero.ReadFile(gen.fs, filepath.Join(gen.settings.Path, filepath.FromSlash(file)))
 	if err != nil {
 		return err
 	}
-	gen.successMessage("Executed `go mod tidy`")
 
-	fmt.Println(ansi.Color("Done!", "green+b"), fmt.Sprintf("\033[3m%s\033[0m", utils.GetRandomPhrase()))
+	err = manifest.WriteBase(gen.fs, gen.settings.Path, file, b)
+	if err != nil {
+		return fmt.Errorf("error writing base of %s: %s", file, err)
+	}
 
 	return nil
 }
 
-// Rollback removes all the files and folders that were created during the generation process
-func (gen *Generator) Rollback() error {
-	for dir := range gen.directories {
-		if err := os.RemoveAll(gen.settings.Path + "/" + dir); err != nil {
-			return err
+// validateSettings makes sure the logger and every enabled adapter, service and flavor is registered
+func (gen *Generator) validateSettings() error {
+	if gen.settings == nil {
+		return errors.New("settings are not set")
+	}
+
+	if _, ok := gen.loggers[gen.settings.Logger]; gen.settings.Logger != "" && !ok {
+		return fmt.Errorf("unknown logger: %s", gen.settings.Logger)
+	}
+
+	for _, adapter := range gen.settings.Adapters {
+		if _, ok := gen.adapters[adapter]; !ok {
+			return fmt.Errorf("unknown adapter: %s", adapter)
+		}
+	}
+
+	for service, flavor := range gen.settings.Services {
+		svc, ok := gen.services[service]
+		if !ok {
+			return fmt.Errorf("unknown service: %s", service)
+		}
+
+		if svc.GetFlavor(flavor) == nil {
+			return fmt.Errorf("unknown flavor %s for service %s", flavor, service)
 		}
 	}
 
-	err := os.Remove(gen.settings.Path + "/go.mod")
+	return gen.CheckConstraints()
+}
+
+// Rollback undoes every change the generator made to the filesystem, including the ones made by the go tool
+// Created files and folders are removed and modified files are restored
+func (gen *Generator) Rollback() error {
+	err := gen.journal.Rollback()
 	if err != nil {
 		return err
 	}
 
-	gen.successMessage("Rolled back changes due to error")
+	gen.emit(EventRollback, "Rolled back changes due to error")
 
 	return nil
 }
@@ -230,22 +431,16 @@ func (gen *Generator) Rollback() error {
 // setModuleVersion sets the module version in the go.mod file
 // If the module is being generated from a template, it will also update the module name to the new module name
 func (gen *Generator) setModuleVersion() error {
-	// Open the go.mod file for reading
-	file, err := os.Open(path.Join(gen.settings.Path, "go.mod"))
-	if err != nil {
-		return err
-	}
-	defer file.Close()
+	filename := path.Join(gen.settings.Path, "go.mod")
 
-	// Create a temporary file for writing the updated contents
-	tmpFile, err := os.Create(path.Join(gen.settings.Path, "go.mod.tmp"))
+	b, err := afero.ReadFile(gen.fs, filename)
 	if err != nil {
 		return err
 	}
-	defer tmpFile.Close()
 
 	// Read the file line by line
-	scanner := bufio.NewScanner(file)
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(b))
 	for scanner.Scan() {
 		line := scanner.Text()
 
@@ -262,11 +457,7 @@ func (gen *Generator) setModuleVersion() error {
 			line = "go " + gen.settings.ModuleVersion
 		}
 
-		// Write the updated line to the temporary file
-		_, err := tmpFile.WriteString(line + "\n")
-		if err != nil {
-			return err
-		}
+		lines = append(lines, line)
 	}
 
 	// Check for any errors during scanning
@@ -274,19 +465,54 @@ func (gen *Generator) setModuleVersion() error {
 		return err
 	}
 
-	// Replace the original file with the updated temporary file
-	err = os.Rename(path.Join(gen.settings.Path, "go.mod.tmp"), path.Join(gen.settings.Path, "go.mod"))
-	if err != nil {
-		return err
-	}
+	// Replace the original file with the updated contents
+	return afero.WriteFile(gen.fs, filename, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
 
-	return nil
+// copyTemplate copies a cloned template to the module path, the .git folder is left out
+func (gen *Generator) copyTemplate(dir string) error {
+	return filepath.WalkDir(dir, func(src string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, src)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(gen.settings.Path, rel)
+
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+
+			return gen.fs.MkdirAll(dst, 0755)
+		}
+
+		// Symlinks and other special files aren't copied
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		b, err := os.ReadFile(src)
+		if err != nil {
+			return err
+		}
+
+		return afero.WriteFile(gen.fs, dst, b, info.Mode().Perm())
+	})
 }
 
 // replaceImports - Replaces imports for template projects
 func (gen *Generator) replaceImports(template string) error {
 	// Walk through all directories and files in the project
-	err := filepath.Walk(gen.settings.Path, func(path string, info os.FileInfo, err error) error {
+	err := afero.Walk(gen.fs, gen.settings.Path, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -302,22 +528,17 @@ func (gen *Generator) replaceImports(template string) error {
 		}
 
 		// Read the file contents
-		b, err := os.ReadFile(path)
+		b, err := afero.ReadFile(gen.fs, path)
 		if err != nil {
 			return err
 		}
 
-		// Replace import strings
-		if gen.useTemplate {
-
-		}
-
-		foundTemplate := gen.templates[template]
-		replaced := strings.Replace(string(b), foundTemplate.GetName(), gen.settings.Module, -1)
+		// Replace import strings, custom templates aren't registered so the template name is used as is
+		replaced := strings.Replace(string(b), template, gen.settings.Module, -1)
 
 		// If the contents have changed, write the updated contents back to the file
 		if replaced != string(b) {
-			err = os.WriteFile(path, []byte(replaced), 0644)
+			err = afero.WriteFile(gen.fs, path, []byte(replaced), 0644)
 			if err != nil {
 				return err
 			}
@@ -333,14 +554,36 @@ func (gen *Generator) replaceImports(template string) error {
 	return nil
 }
 
-// Execute a given command
-func (gen *Generator) executeCommand(cmdStr string) error {
-	cmd := exec.Command("sh", "-c", cmdStr)
+// Execute a given command in the module path
+// The files the command creates or modifies, relative to the module path, are recorded in the journal first
+func (gen *Generator) executeCommand(ctx context.Context, cmdStr string, files ...string) error {
+	for _, file := range files {
+		err := gen.journal.Record(path.Join(gen.settings.Path, file))
+		if err != nil {
+			return err
+		}
+	}
 
-	cmd.Dir = gen.settings.Path
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		return errors.New(string(out))
+	_, err := gen.runner(ctx, gen.settings.Path, cmdStr)
+	return err
+}
+
+// runCommands runs the commands of the modules that implement domain.CommandsI, one after another
+// The files the commands create end up in the packages of the modules, go.mod and go.sum are recorded because tools may update them
+func (gen *Generator) runCommands(ctx context.Context, modules []module) error {
+	for _, m := range modules {
+		cmds, ok := m.ModuleI.(domain.CommandsI)
+		if !ok {
+			continue
+		}
+
+		for _, cmd := range cmds.Commands(m.ns) {
+			err := gen.executeCommand(ctx, cmd, "go.mod", "go.sum")
+			if err != nil {
+				return fmt.Errorf("error executing `%s` for %s: %s", cmd, m.name, err)
+			}
+			gen.successMessage(fmt.Sprintf("Executed `%s`", cmd))
+		}
 	}
 
 	return nil
@@ -359,27 +602,23 @@ func (gen *Generator) generateFolderStructure() error {
 			"app",
 			"domain",
 		},
-		"pkg": {
-			"logger",
-		},
+		"pkg": nil,
 	}
 
 	// Append the adapters to the pkg directory
 	directories["pkg"] = append(directories["pkg"], gen.settings.Adapters...)
 
-	gen.directories = directories
-
 	// Loop through the map and create directories and sub-directories
 	for parentDir, subDirs := range directories {
-		if _, err := os.Stat(gen.settings.Path + "/" + parentDir); os.IsNotExist(err) {
-			err := os.Mkdir(gen.settings.Path+"/"+parentDir, 0755)
+		if _, err := gen.fs.Stat(gen.settings.Path + "/" + parentDir); os.IsNotExist(err) {
+			err := gen.fs.Mkdir(gen.settings.Path+"/"+parentDir, 0755)
 			if err != nil {
 				return fmt.Errorf("error creating folder: %s", err)
 			}
 
 			if subDirs != nil {
 				for _, subfolderName := range subDirs {
-					err := os.Mkdir(gen.settings.Path+"/"+parentDir+"/"+subfolderName, 0755)
+					err := gen.fs.Mkdir(gen.settings.Path+"/"+parentDir+"/"+subfolderName, 0755)
 					if err != nil {
 						return fmt.Errorf("error creating sub-folder: %s", err)
 					}
@@ -398,25 +637,35 @@ func (gen *Generator) generateMainFile() error {
 	mainFile.Var().Id("Version").Op("=").Lit("dev")
 	mainFile.Var().Id("Timestamp").Op("=").Lit("unknown")
 
+	// The logger is created from its config section and passed to the Run function
+	logger, hasLogger := gen.loggerNamespace()
+	runArgs := []Code{Id("gCtx"), Id("cancel"), Id("cfg")}
+
 	// main function
-	mainFile.Func().Id("main").Params().Block(
-		List(Id("cfg"), Err()).Op(":=").Qual(gen.settings.Module+"/config", "New").Call(Id("Version")),
-		If(Err().Op("!=").Nil()).Block(
+	mainFile.Func().Id("main").Params().BlockFunc(func(g *Group) {
+		g.List(Id("cfg"), Err()).Op(":=").Qual(gen.settings.Module+"/config", "New").Call(Id("Version"))
+		g.If(Err().Op("!=").Nil()).Block(
 			Qual("fmt", "Println").Call(Lit("main.config.New()"), Err()),
-		),
-		Line(),
-		Line().Comment("TODO: Initialize logger here and pass it to the Run function"),
-		Line(),
-		Id("gCtx").Op(",").Id("cancel").Op(":=").Qual("context", "WithCancel").Params(Qual("context", "Background").Call()),
-		Line(),
-		Qual(gen.settings.Module+"/internal/app", "Run").Call(Id("gCtx"), Id("cancel"), Id("cfg")),
-	)
+		)
+		g.Line()
+
+		if hasLogger {
+			g.Id("l").Op(":=").Qual(logger.Path(), "New").Call(Id("cfg").Dot("Logger").Dot("Level"), Id("cfg").Dot("Logger").Dot("Format"))
+			runArgs = append(runArgs, Id("l"))
+		} else {
+			g.Line().Comment("TODO: Initialize logger here and pass it to the Run function")
+		}
+
+		g.Line()
+		g.Id("gCtx").Op(",").Id("cancel").Op(":=").Qual("context", "WithCancel").Params(Qual("context", "Background").Call())
+		g.Line()
+		g.Qual(gen.settings.Module+"/internal/app", "Run").Call(runArgs...)
+	})
 
 	// Save the file
-	err := mainFile.Save(gen.settings.Path + "/cmd/app/main.go")
+	err := utils.SaveFile(gen.fs, mainFile, gen.settings.Path+"/cmd/app/main.go")
 	if err != nil {
-		fmt.Println(err.Error())
-		return err
+		return fmt.Errorf("error creating cmd/app/main.go file: %s", err)
 	}
 
 	return nil
@@ -434,48 +683,51 @@ func (gen *Generator) createInternalAppFile() error {
 	var shutdownServices []Code
 	var shutdownAdapters []Code
 
+	logger, hasLogger := gen.loggerNamespace()
+
+	// Loggers that marshal values to JSON would log the number of the signal
+	signal := Id("stop")
+	if hasLogger {
+		signal = Id("stop").Dot("String").Call()
+	}
+
 	selectBranches = append(selectBranches, Case(Id("stop").Op(":=").Op("<-").Id("interrupt")).Block(
-		Qual("fmt", "Println").Call(Lit("app.Run - received signal"), Id("stop")),
+		logger.Log("Info", "app.Run - received signal", Lit("signal"), signal),
 	))
 
-	for _, adapter := range gen.adapters {
-		if gen.settings.IsAdapterChecked(adapter.GetName()) {
-			init = append(init, adapter.AppInit(gen.settings.Module)...)
-			init = append(init, Line())
+	// Services assign to err without declaring it
+	needsErr := false
 
-			selectBranches = append(selectBranches, adapter.AppSelect(gen.settings.Module), Line())
-
-			shutdownAdapters = append(shutdownAdapters, adapter.AppShutdown(gen.settings.Module)...)
-			shutdownAdapters = append(shutdownAdapters, Line())
+	for _, m := range gen.modules(gen.settings) {
+		if m.service != "" && usesErr(m, m.ns) {
+			needsErr = true
 		}
-	}
-
-	for _, service := range gen.services {
-		if gen.settings.IsServiceChecked(service.GetName()) {
-			flavorStr := gen.settings.Services[service.GetName()]
-			flavor := service.GetFlavors()[flavorStr]
 
-			init = append(init, flavor.AppInit(gen.settings.Module)...)
-			init = append(init, Line())
+		init = append(init, m.AppInit(m.ns)...)
+		init = append(init, Line())
 
-			selectBranches = append(selectBranches, flavor.AppSelect(gen.settings.Module), Line())
+		selectBranches = append(selectBranches, m.AppSelect(m.ns), Line())
 
-			shutdownServices = append(shutdownServices, flavor.AppShutdown(gen.settings.Module)...)
+		// Services are shut down before the adapters they might use
+		if m.service != "" {
+			shutdownServices = append(shutdownServices, m.AppShutdown(m.ns)...)
 			shutdownServices = append(shutdownServices, Line())
+		} else {
+			shutdownAdapters = append(shutdownAdapters, m.AppShutdown(m.ns)...)
+			shutdownAdapters = append(shutdownAdapters, Line())
 		}
 	}
 
 	f := NewFilePathName("internal/app", "app")
 
-	// Anonymous import for SQL driver
-	// Only doing it if SQL is used
-	if gen.settings.IsAdapterChecked("mariadb") || gen.settings.IsAdapterChecked("mysql") {
-		f.Anon("github.com/go-sql-driver/mysql")
+	params := []Code{Id("gCtx").Qual("context", "Context"), Id("cancel").Qual("context", "CancelFunc"), Id("cfg").Add(utils.Jptr).Qual(gen.settings.Module+"/config", "Config")}
+	if hasLogger {
+		params = append(params, Id("l").Qual(logger.Path(), "Interface"))
 	}
 
 	// Create the main Run function
-	f.Func().Id("Run").Params(Id("gCtx").Qual("context", "Context"), Id("cancel").Qual("context", "CancelFunc"), Id("cfg").Add(utils.Jptr).Qual(gen.settings.Module+"/config", "Config")).BlockFunc(func(g *Group) {
-		if len(gen.settings.Adapters) != 0 && len(gen.settings.Services) != 0 {
+	f.Func().Id("Run").Params(params...).BlockFunc(func(g *Group) {
+		if needsErr {
 			g.Var().Err().Error()
 		}
 
@@ -499,7 +751,7 @@ func (gen *Generator) createInternalAppFile() error {
 		g.Add(shutdownAdapters...)
 	})
 
-	err := f.Save(gen.settings.Path + "/internal/app/app.go")
+	err := utils.SaveFile(gen.fs, f, gen.settings.Path+"/internal/app/app.go")
 	if err != nil {
 		return fmt.Errorf("error creating internal/app/app.go file: %s", err)
 	}
@@ -513,18 +765,24 @@ func (gen *Generator) createConfigGoFile() error {
 	// Add the config struct parts for the various pieces
 	var configs []Code
 
-	for _, adapter := range gen.adapters {
-		if gen.settings.IsAdapterChecked(adapter.GetName()) {
-			configs = append(configs, adapter.ConfigGo())
-		}
+	if logger, ok := gen.loggers[gen.settings.Logger]; ok {
+		configs = append(configs, logger.ConfigGo())
 	}
 
-	/* for _, service := range gen.services {
-		flavorStr := gen.settings.Services[service.GetName()]
-		flavor := service.GetFlavors()[flavorStr]
+	for _, adapter := range gen.enabledAdapters() {
+		configs = append(configs, adapter.ConfigGo())
+	}
 
-		configs = append(configs, service.ConfigGo())
-	} */
+	if m, ok := gen.migrateModule(gen.settings); ok {
+		configs = append(configs, m.ConfigGo())
+	}
+
+	// Flavors without a config section return nil
+	for _, flavor := range gen.enabledFlavors() {
+		if config := flavor.ConfigGo(); config != nil {
+			configs = append(configs, config)
+		}
+	}
 
 	// The config struct
 	f.Type().Id("Config").Struct(
@@ -564,7 +822,7 @@ func (gen *Generator) createConfigGoFile() error {
 	)
 
 	// Save the file
-	err := f.Save(gen.settings.Path + "/config/config.go")
+	err := utils.SaveFile(gen.fs, f, gen.settings.Path+"/config/config.go")
 	if err != nil {
 		return fmt.Errorf("error creating config/config.go file: %s", err)
 	}
@@ -576,18 +834,24 @@ func (gen *Generator) createConfigYamlFile() error {
 	// Add the config struct parts for the various pieces
 	var configs []map[string]interface{}
 
+	if logger, ok := gen.loggers[gen.settings.Logger]; ok {
+		configs = append(configs, logger.ConfigYAML())
+	}
+
 	// Loop over adapters and get its config
-	for _, adapter := range gen.adapters {
-		if gen.settings.IsAdapterChecked(adapter.GetName()) {
-			configs = append(configs, adapter.ConfigYAML())
-		}
+	for _, adapter := range gen.enabledAdapters() {
+		configs = append(configs, adapter.ConfigYAML())
+	}
+
+	if m, ok := gen.migrateModule(gen.settings); ok {
+		configs = append(configs, m.ConfigYAML())
 	}
 
-	/* for _, service := range gen.services {
-		if gen.settings.IsServiceChecked(service.GetName()) {
-			configs = append(configs, service.ConfigYAML())
+	for _, flavor := range gen.enabledFlavors() {
+		if config := flavor.ConfigYAML(); config != nil {
+			configs = append(configs, config)
 		}
-	} */
+	}
 
 	// Marshal each map into a separate YAML document
 	var yamlDocs []string
@@ -610,12 +874,12 @@ func (gen *Generator) createConfigYamlFile() error {
 	}
 
 	// Write the YAML data to a file
-	err := os.WriteFile(gen.settings.Path+"/config/config.yaml", []byte(finalYaml), 0600)
+	err := afero.WriteFile(gen.fs, gen.settings.Path+"/config/config.yaml", []byte(finalYaml), 0600)
 	if err != nil {
 		return fmt.Errorf("error creating config/config.yaml file: %s", err)
 	}
 
-	err = os.WriteFile(gen.settings.Path+"/config/config.dev.yaml", []byte(finalYaml), 0600)
+	err = afero.WriteFile(gen.fs, gen.settings.Path+"/config/config.dev.yaml", []byte(finalYaml), 0600)
 	if err != nil {
 		return fmt.Errorf("error creating config/config.dev.yaml file: %s", err)
 	}
@@ -624,18 +888,91 @@ func (gen *Generator) createConfigYamlFile() error {
 }
 
 // copyFiles - Copies all the needed adapters, services, controllers and config files
+// Every adapter and service is generated even if one fails, the errors are returned together
 func (gen *Generator) copyFiles() error {
-	for _, adapter := range gen.adapters {
-		if gen.settings.IsAdapterChecked(adapter.GetName()) {
-			adapter.Service(gen.settings.Module, gen.settings.Path)
+	var errs []error
+
+	for _, m := range gen.modules(gen.settings) {
+		err := m.Service(gen.fs, m.ns, gen.settings.Path)
+		if err != nil {
+			errs = append(errs, err)
 		}
 	}
 
-	for _, service := range gen.services {
-		if gen.settings.IsServiceChecked(service.GetName()) {
-			service.GetFlavor(gen.settings.Services[service.GetName()]).Service(gen.settings.Module, gen.settings.Path)
+	return errors.Join(errs...)
+}
+
+// runCode is the code adapters and flavors add to the Run function
+type runCode interface {
+	AppInit(ns domain.Namespace) []Code
+	AppSelect(ns domain.Namespace) Code
+	AppShutdown(ns domain.Namespace) []Code
+}
+
+// usesErr reports if the code a module adds to the Run function uses the err variable
+func usesErr(m runCode, ns domain.Namespace) bool {
+	code := Null().Add(m.AppInit(ns)...).Add(m.AppSelect(ns)).Add(m.AppShutdown(ns)...)
+	return errIdent.MatchString(fmt.Sprintf("%#v", code))
+}
+
+// errIdent matches the err identifier in rendered code
+var errIdent = regexp.MustCompile(`\berr\b`)
+
+// loggerNamespace returns the namespace of the logger, without one the namespace still prints the messages of Log with fmt
+func (gen *Generator) loggerNamespace() (domain.Namespace, bool) {
+	for _, m := range gen.modules(gen.settings) {
+		if m.logger {
+			return m.ns, true
 		}
 	}
 
-	return nil
+	return domain.Namespace{Module: gen.settings.Module}, false
+}
+
+// enabledAdapters returns the enabled adapters in registry order, sorted by name, so generated code is the same on every run
+func (gen *Generator) enabledAdapters() []domain.ModuleI {
+	var adapters []domain.ModuleI
+	for _, name := range sortedKeys(gen.adapters) {
+		if gen.settings.IsAdapterChecked(name) {
+			adapters = append(adapters, gen.adapters[name])
+		}
+	}
+
+	return adapters
+}
+
+// enabledServices returns the enabled services in registry order, sorted by name, so generated code is the same on every run
+func (gen *Generator) enabledServices() []domain.ServiceI {
+	var services []domain.ServiceI
+	for _, name := range sortedKeys(gen.services) {
+		if gen.settings.IsServiceChecked(name) {
+			services = append(services, gen.services[name])
+		}
+	}
+
+	return services
+}
+
+// enabledFlavors returns the chosen flavor of every enabled service, in the same order as enabledServices
+func (gen *Generator) enabledFlavors() []domain.FlavorI {
+	var flavors []domain.FlavorI
+	for _, service := range gen.enabledServices() {
+		flavor := service.GetFlavor(gen.settings.Services[service.GetName()])
+		if flavor != nil {
+			flavors = append(flavors, flavor)
+		}
+	}
+
+	return flavors
+}
+
+// sortedKeys returns the keys of a map in alphabetical order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
 }
@@ -0,0 +1,308 @@
+package generator
+
+import (
+	"context"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+
+	"github.com/mahcks/gowizard/pkg/domain"
+	"github.com/mahcks/gowizard/pkg/manifest"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata/golden")
+
+// goldenCase is a set of settings that is generated and compared against testdata/golden/<name>
+type goldenCase struct {
+	name       string
+	adapters   []string
+	services   map[string]string
+	logger     string
+	migrations string // adapter the migrations are scaffolded for
+	goVersion  string // 1.20, or the newest version one of the modules requires
+}
+
+// goldenCases returns a case for every adapter, every flavor of every service, every logger and all of them that can be combined
+// The logger cases log the messages of an adapter and a service, the migrations cases scaffold them for every SQL adapter
+func goldenCases(gen *Generator) []goldenCase {
+	cases := []goldenCase{{name: "empty"}}
+
+	all := goldenCase{
+		name:     "all",
+		adapters: []string{"mariadb", "mongodb", "postgres", "redis", "sql"},
+		services: map[string]string{},
+	}
+	for _, adapter := range sortedKeys(gen.adapters) {
+		cases = append(cases, goldenCase{name: "adapter-" + adapter, adapters: []string{adapter}})
+	}
+
+	for _, service := range sortedKeys(gen.services) {
+		flavors := sortedKeys(gen.services[service].GetFlavors())
+		for _, flavor := range flavors {
+			cases = append(cases, goldenCase{
+				name:     "service-" + service + "-" + flavor,
+				services: map[string]string{service: flavor},
+			})
+		}
+		all.services[service] = flavors[0]
+	}
+
+	for i, logger := range sortedKeys(gen.loggers) {
+		cases = append(cases, goldenCase{
+			name:     "logger-" + logger,
+			adapters: []string{"redis"},
+			services: map[string]string{"rest": "gin"},
+			logger:   logger,
+		})
+
+		if i == 0 {
+			all.logger = logger
+		}
+	}
+
+	for _, adapter := range gen.migrationAdapters() {
+		cases = append(cases, goldenCase{name: "migrations-" + adapter, adapters: []string{adapter}, migrations: adapter})
+	}
+	all.migrations = "postgres"
+
+	// Modules that are generated together share app.go and the config, the servers need their own ports
+	cases = append(cases,
+		goldenCase{name: "rest-gql", services: map[string]string{"rest": "gin", "gql": "gqlgen"}},
+		goldenCase{name: "postgres-mariadb", adapters: []string{"mariadb", "postgres"}},
+		all,
+	)
+
+	for i := range cases {
+		settings := &domain.Settings{Adapters: cases[i].adapters, Services: cases[i].services, Logger: cases[i].logger, Migrations: cases[i].migrations}
+
+		cases[i].goVersion = "1.20"
+		for _, m := range gen.modules(settings) {
+			if required := m.Constraints().GoVersion; required != "" && versionLess(cases[i].goVersion, required) {
+				cases[i].goVersion = required
+			}
+		}
+	}
+
+	return cases
+}
+
+func TestGenerateGolden(t *testing.T) {
+	for _, tc := range goldenCases(NewGenerator()) {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			gen := NewGenerator(
+				WithModule("github.com/gowizard/golden"),
+				WithGoVersion(tc.goVersion),
+				WithPath(dir),
+				WithAdapters(tc.adapters...),
+				WithServices(tc.services),
+				WithLogger(tc.logger),
+				WithMigrations(tc.migrations),
+				WithDryRun(true),
+				WithEventHandler(func(event Event) {
+					// Verify warns about the packages it can't check, the golden projects have to be checked completely
+					if event.Kind == EventWarning {
+						t.Errorf("the project can't be verified completely: %s", event.Message)
+					}
+				}),
+			)
+
+			err := gen.Generate(context.Background())
+			if err != nil {
+				t.Fatalf("error generating project: %s", err)
+			}
+
+			got := readTree(t, dir)
+			goldenDir := filepath.Join("testdata", "golden", tc.name)
+
+			if *update {
+				err = os.RemoveAll(goldenDir)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				for file, content := range got {
+					path := filepath.Join(goldenDir, filepath.FromSlash(file)+".golden")
+					if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+						t.Fatal(err)
+					}
+					if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+						t.Fatal(err)
+					}
+				}
+				return
+			}
+
+			want := readTree(t, goldenDir)
+			for file, content := range want {
+				file = strings.TrimSuffix(file, ".golden")
+				if _, ok := got[file]; !ok {
+					t.Errorf("%s was not generated", file)
+					continue
+				}
+
+				if got[file] != content {
+					t.Errorf("%s doesn't match its golden file, run `go test ./pkg/generator -update` if the change is intended\n--- got\n%s\n--- want\n%s", file, got[file], content)
+				}
+			}
+
+			for file := range got {
+				if _, ok := want[file+".golden"]; !ok {
+					t.Errorf("%s was generated but has no golden file", file)
+				}
+			}
+
+			// Type-checking the standard library from source takes a while
+			if testing.Short() {
+				return
+			}
+
+			compileErrs, err := gen.Verify()
+			if err != nil {
+				t.Fatalf("error verifying project: %s", err)
+			}
+
+			for _, compileErr := range compileErrs {
+				t.Errorf("generated project doesn't compile: %s", compileErr)
+			}
+		})
+	}
+}
+
+// readTree reads every file below dir keyed by its slash separated path, the manifest and bases are left out since they contain the temp dir
+func readTree(t *testing.T, dir string) map[string]string {
+	t.Helper()
+
+	files := map[string]string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if rel == ".gowizard" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if rel == manifest.Filename {
+			return nil
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		files[rel] = string(b)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("error reading %s: %s", dir, err)
+	}
+
+	return files
+}
+
+func TestGenerateCommands(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	var commands []string
+	runner := func(ctx context.Context, dir, command string) (string, error) {
+		commands = append(commands, command)
+
+		// go mod init is the only command the generator reads the result of
+		if strings.HasPrefix(command, "go mod init") {
+			return "", afero.WriteFile(fs, dir+"/go.mod", []byte("module github.com/gowizard/commands\n\ngo 1.20\n"), 0644)
+		}
+
+		return "", nil
+	}
+
+	gen := NewGenerator(
+		WithModule("github.com/gowizard/commands"),
+		WithGoVersion("1.20"),
+		WithPath("/project"),
+		WithServices(map[string]string{"gql": "gqlgen"}),
+		WithFs(fs),
+		WithCommandRunner(runner),
+	)
+
+	err := gen.Generate(context.Background())
+	if err != nil {
+		t.Fatalf("error generating project: %s", err)
+	}
+
+	expected := []string{
+		"go mod init github.com/gowizard/commands",
+		"go mod tidy",
+		"go generate ./pkg/gqlserver/...",
+		"go mod tidy",
+	}
+	if strings.Join(commands, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected the commands %q, got %q", expected, commands)
+	}
+}
+
+func TestGenerateRecordsFiles(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	const content = "module: github.com/gowizard/files\n"
+	generateProject(t, fs, WithFile("gowizard.yaml", []byte(content)))
+
+	assertFile(t, fs, "/project/gowizard.yaml", content)
+	assertFile(t, fs, manifest.BasePath("/project", "gowizard.yaml"), content)
+
+	m, err := manifest.Load(fs, "/project")
+	if err != nil {
+		t.Fatalf("error loading manifest: %s", err)
+	}
+	if m.Files["gowizard.yaml"] != manifest.Hash([]byte(content)) {
+		t.Errorf("expected gowizard.yaml to be recorded in the manifest")
+	}
+}
+
+func TestUseTemplateDryRun(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	var commands []string
+	runner := func(ctx context.Context, dir, command string) (string, error) {
+		commands = append(commands, command)
+		return "", nil
+	}
+
+	gen := NewGenerator(
+		WithModule("github.com/gowizard/template"),
+		WithGoVersion("1.20"),
+		WithPath("/project"),
+		WithFs(fs),
+		WithDryRun(true),
+		WithCommandRunner(runner),
+	)
+
+	err := gen.UseTemplate(context.Background(), "github.com/gowizard/template", true)
+	if err != nil {
+		t.Fatalf("error using template: %s", err)
+	}
+
+	if len(commands) != 0 {
+		t.Errorf("expected a dry run not to run commands, got %q", commands)
+	}
+
+	if exists, _ := afero.Exists(fs, "/project"); exists {
+		t.Errorf("expected a dry run of a template not to write the project")
+	}
+}
@@ -0,0 +1,170 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/mahcks/gowizard/pkg/adapters"
+	"github.com/mahcks/gowizard/pkg/domain"
+)
+
+// module is the logger, an enabled adapter or flavor together with its namespace
+type module struct {
+	domain.ModuleI
+	name    string // name of the adapter or logger, or service/flavor for flavors
+	service string // service of a flavor, empty for adapters
+	logger  bool   // the logger of the project, its namespace is named logger
+	ns      domain.Namespace
+}
+
+// modules returns the logger, the enabled adapters, the migrations and the flavors in the order they're generated in, each one with its namespace
+func (gen *Generator) modules(settings *domain.Settings) []module {
+	var modules []module
+
+	if m, ok := gen.loggerModule(settings.Logger); ok {
+		modules = append(modules, m)
+	}
+
+	for _, name := range sortedKeys(gen.adapters) {
+		if settings.IsAdapterChecked(name) {
+			modules = append(modules, gen.adapterModule(name))
+		}
+	}
+
+	if m, ok := gen.migrateModule(settings); ok {
+		modules = append(modules, m)
+	}
+
+	for _, name := range sortedKeys(gen.services) {
+		if !settings.IsServiceChecked(name) {
+			continue
+		}
+
+		m, ok := gen.flavorModule(name, settings.Services[name])
+		if ok {
+			modules = append(modules, m)
+		}
+	}
+
+	allocate(settings, modules)
+
+	return modules
+}
+
+// loggerModule returns the logger of the project as a module, there is none when the name is empty or unknown
+func (gen *Generator) loggerModule(name string) (module, bool) {
+	logger, ok := gen.loggers[name]
+	if !ok {
+		return module{}, false
+	}
+
+	return module{
+		ModuleI: logger,
+		name:    name,
+		logger:  true,
+		ns:      domain.Namespace{Name: "logger"},
+	}, true
+}
+
+// adapterModule returns an adapter as a module, its namespace is named after the adapter
+func (gen *Generator) adapterModule(name string) module {
+	return module{
+		ModuleI: gen.adapters[name],
+		name:    name,
+		ns:      domain.Namespace{Name: name},
+	}
+}
+
+// migrateModule returns the migrations of the adapter the settings name, there are none when it doesn't support them
+// The adapter isn't guessed, so adding another SQL adapter later doesn't move the migrations
+func (gen *Generator) migrateModule(settings *domain.Settings) (module, bool) {
+	database, ok := gen.adapters[settings.Migrations].(domain.MigrationsI)
+	if !ok {
+		return module{}, false
+	}
+
+	return module{
+		ModuleI: adapters.NewMigrateModule(settings.Migrations, database),
+		name:    "migrate",
+		ns:      domain.Namespace{Name: "migrate"},
+	}, true
+}
+
+// migrationAdapters returns the names of the adapters migrations can be scaffolded for, sorted by name
+func (gen *Generator) migrationAdapters() []string {
+	var names []string
+	for _, name := range sortedKeys(gen.adapters) {
+		if _, ok := gen.adapters[name].(domain.MigrationsI); ok {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// flavorModule returns the flavor of a service as a module, its namespace is named after the service
+func (gen *Generator) flavorModule(service, flavor string) (module, bool) {
+	svc, ok := gen.services[service]
+	if !ok {
+		return module{}, false
+	}
+
+	flv := svc.GetFlavor(flavor)
+	if flv == nil {
+		return module{}, false
+	}
+
+	return module{
+		ModuleI: flv,
+		name:    service + "/" + flavor,
+		service: service,
+		ns:      domain.Namespace{Name: service},
+	}, true
+}
+
+// allocate gives every module the name and package of its namespace, the first module that wants one gets it
+// Later modules get a numbered one, i.e. pkg/httpserver2, so any combination of modules compiles
+// Modules that are added to an existing project are allocated last, so the existing ones keep theirs
+func allocate(settings *domain.Settings, modules []module) {
+	names := make(map[string]bool)
+	packages := make(map[string]bool)
+
+	for i := range modules {
+		m := &modules[i]
+
+		pkg := "pkg/" + baseName(m)
+		if owned := m.Constraints().Packages; len(owned) != 0 {
+			pkg = owned[0]
+		}
+
+		m.ns = domain.Namespace{
+			Module:  settings.Module,
+			Name:    unique(names, baseName(m)),
+			Package: unique(packages, pkg),
+			Logger:  settings.Logger,
+		}
+	}
+}
+
+// baseName is the namespace name a module wants, adapters are named after themselves and flavors after their service
+func baseName(m *module) string {
+	if m.logger {
+		return "logger"
+	}
+
+	if m.service != "" {
+		return m.service
+	}
+
+	return m.name
+}
+
+// unique returns name, or name with the lowest number after it that isn't taken yet, and marks it as taken
+func unique(taken map[string]bool, name string) string {
+	candidate := name
+	for n := 2; taken[candidate]; n++ {
+		candidate = fmt.Sprintf("%s%d", name, n)
+	}
+	taken[candidate] = true
+
+	return candidate
+}
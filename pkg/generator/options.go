@@ -0,0 +1,166 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"github.com/mahcks/gowizard/pkg/journal"
+)
+
+// Option configures a generator, see NewGenerator and Configure
+type Option func(gen *Generator)
+
+// EventKind is the kind of progress event the generator emits
+type EventKind string
+
+const (
+	EventStep     EventKind = "step"     // A step finished, i.e. a file was generated or a command was executed
+	EventRollback EventKind = "rollback" // Changes were rolled back after an error
+	EventWarning  EventKind = "warning"  // Something the user should know about, i.e. packages that couldn't be verified
+	EventDone     EventKind = "done"     // The project was generated
+)
+
+// Event is a progress update of the generator
+type Event struct {
+	Kind    EventKind
+	Message string
+}
+
+// EventHandler receives the progress events of the generator
+type EventHandler func(event Event)
+
+// CommandRunner executes a shell command in a directory and returns its output
+type CommandRunner func(ctx context.Context, dir, command string) (string, error)
+
+// WithModule - Name of the module to generate, i.e. github.com/user/module
+func WithModule(module string) Option {
+	return func(gen *Generator) {
+		gen.settings.Module = module
+	}
+}
+
+// WithGoVersion - Go version written to go.mod, i.e. 1.20
+func WithGoVersion(version string) Option {
+	return func(gen *Generator) {
+		gen.settings.ModuleVersion = version
+	}
+}
+
+// WithPath - Folder the module is generated in
+func WithPath(path string) Option {
+	return func(gen *Generator) {
+		gen.settings.Path = path
+	}
+}
+
+// WithAdapters - Adapters to generate, i.e. redis
+func WithAdapters(adapters ...string) Option {
+	return func(gen *Generator) {
+		gen.settings.Adapters = adapters
+	}
+}
+
+// WithServices - Services to generate, the key is the service and the value its flavor, i.e. rest: gin
+func WithServices(services map[string]string) Option {
+	return func(gen *Generator) {
+		gen.settings.Services = services
+	}
+}
+
+// WithLogger - Logger the project logs with, i.e. slog, without one the modules print with fmt
+func WithLogger(logger string) Option {
+	return func(gen *Generator) {
+		gen.settings.Logger = logger
+	}
+}
+
+// WithMigrations - Adapter of the SQL database to scaffold migrations for, i.e. postgres, none without one
+func WithMigrations(adapter string) Option {
+	return func(gen *Generator) {
+		gen.settings.Migrations = adapter
+	}
+}
+
+// WithFile - A file that is written to the project with the generated ones, name is relative to the project, i.e. the spec it was generated from
+// It's recorded in the manifest like the generated files
+func WithFile(name string, content []byte) Option {
+	return func(gen *Generator) {
+		if gen.files == nil {
+			gen.files = map[string][]byte{}
+		}
+		gen.files[name] = content
+	}
+}
+
+// WithVersion - Version of gowizard that is recorded in the manifest of generated projects
+func WithVersion(version string) Option {
+	return func(gen *Generator) {
+		gen.version = version
+	}
+}
+
+// WithFs - Filesystem the project is written to, defaults to the OS filesystem
+func WithFs(fs afero.Fs) Option {
+	return func(gen *Generator) {
+		gen.journal = journal.NewFs(fs)
+		gen.fs = gen.journal
+	}
+}
+
+// WithDryRun - In a dry run the go tool isn't executed, only the filesystem is written to
+// Combined with an in-memory filesystem nothing is written to disk
+func WithDryRun(dryRun bool) Option {
+	return func(gen *Generator) {
+		gen.dryRun = dryRun
+	}
+}
+
+// WithEventHandler - Receives the progress events, without one the generator is silent
+func WithEventHandler(handler EventHandler) Option {
+	return func(gen *Generator) {
+		gen.events = handler
+	}
+}
+
+// WithCommandRunner - Executes the go and git commands, defaults to running them with sh
+func WithCommandRunner(runner CommandRunner) Option {
+	return func(gen *Generator) {
+		gen.runner = runner
+	}
+}
+
+// Configure - Applies options to an existing generator, i.e. once the wizard collected the settings
+func (gen *Generator) Configure(opts ...Option) {
+	for _, opt := range opts {
+		opt(gen)
+	}
+}
+
+// emit sends an event to the event handler, if there is one
+func (gen *Generator) emit(kind EventKind, msg string) {
+	if gen.events == nil {
+		return
+	}
+
+	gen.events(Event{Kind: kind, Message: msg})
+}
+
+// runShell is the default CommandRunner, it runs the command with sh and returns its trimmed output
+func runShell(ctx context.Context, dir, command string) (string, error) {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", errors.New(string(out))
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
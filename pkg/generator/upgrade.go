@@ -0,0 +1,224 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"github.com/mahcks/gowizard/pkg/manifest"
+	"github.com/spf13/afero"
+)
+
+// UpgradeStatus is what happened to a file during an upgrade
+type UpgradeStatus string
+
+const (
+	UpgradeAdded     UpgradeStatus = "added"     // File is new in the generator output
+	UpgradeUpdated   UpgradeStatus = "updated"   // File wasn't changed locally and was replaced
+	UpgradeMerged    UpgradeStatus = "merged"    // Local changes and generator changes were merged cleanly
+	UpgradeConflict  UpgradeStatus = "conflict"  // Local changes and generator changes conflict, the file has conflict markers
+	UpgradeSkipped   UpgradeStatus = "skipped"   // File was deleted locally and is left alone
+	UpgradeUnchanged UpgradeStatus = "unchanged" // File is already up to date
+)
+
+// UpgradeResult is the outcome of upgrading a single file
+type UpgradeResult struct {
+	File   string        // Slash separated path relative to the project
+	Status UpgradeStatus // What happened to the file
+	Note   string        // Extra information, i.e. where the new version was written to
+}
+
+// Upgrade - Re-runs the generator for a loaded project and three-way merges the output into it
+// The base of the merge is the content kept in the manifest, files with conflicts get conflict markers
+func (gen *Generator) Upgrade(ctx context.Context) ([]UpgradeResult, error) {
+	if gen.manifest == nil {
+		return nil, manifest.ErrNotFound
+	}
+
+	if gen.manifest.Template != nil {
+		return nil, errors.New("projects generated from a template can't be upgraded")
+	}
+
+	err := gen.validateSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate the project again in memory with the current adapters and flavors
+	tmpDir := "/gowizard-upgrade"
+
+	settings := *gen.settings
+	settings.Path = tmpDir
+
+	tmpGen := *gen
+	tmpGen.settings = &settings
+	tmpGen.Configure(WithFs(afero.NewMemMapFs()), WithEventHandler(nil))
+
+	err = tmpGen.generateFiles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error generating project: %s", err)
+	}
+
+	generated, err := manifest.HashFiles(tmpGen.fs, tmpDir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(generated))
+	for file := range generated {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	var results []UpgradeResult
+	conflicts := 0
+	for _, file := range files {
+		theirs, err := afero.ReadFile(tmpGen.fs, filepath.Join(tmpDir, filepath.FromSlash(file)))
+		if err != nil {
+			return results, err
+		}
+
+		result, err := gen.upgradeFile(ctx, file, theirs)
+		if err != nil {
+			return results, fmt.Errorf("error upgrading %s: %s", file, err)
+		}
+		results = append(results, result)
+
+		if result.Status == UpgradeConflict {
+			conflicts++
+		}
+
+		// What was generated now is the base of the next upgrade
+		gen.manifest.Files[file] = generated[file]
+		err = manifest.WriteBase(gen.fs, gen.settings.Path, file, theirs)
+		if err != nil {
+			return results, err
+		}
+	}
+
+	gen.manifest.Version = gen.version
+	err = gen.manifest.Save(gen.fs, gen.settings.Path)
+	if err != nil {
+		return results, fmt.Errorf("error writing %s: %s", manifest.Filename, err)
+	}
+
+	if conflicts != 0 || gen.dryRun {
+		return results, nil
+	}
+
+	err = gen.executeCommand(ctx, "go mod tidy", "go.mod", "go.sum")
+	if err != nil {
+		return results, err
+	}
+	gen.successMessage("Executed `go mod tidy`")
+
+	return results, nil
+}
+
+// upgradeFile merges the newly generated content of a file into the project
+func (gen *Generator) upgradeFile(ctx context.Context, file string, theirs []byte) (UpgradeResult, error) {
+	result := UpgradeResult{File: file}
+	path := filepath.Join(gen.settings.Path, filepath.FromSlash(file))
+
+	ours, err := afero.ReadFile(gen.fs, path)
+	if errors.Is(err, fs.ErrNotExist) {
+		if _, generated := gen.manifest.Files[file]; generated {
+			result.Status = UpgradeSkipped
+			result.Note = "deleted locally"
+			return result, nil
+		}
+
+		err = gen.fs.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			return result, err
+		}
+
+		result.Status = UpgradeAdded
+		return result, afero.WriteFile(gen.fs, path, theirs, 0644)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	if bytes.Equal(ours, theirs) {
+		result.Status = UpgradeUnchanged
+		return result, nil
+	}
+
+	// Nobody touched the file since it was generated
+	if gen.manifest.Files[file] == manifest.Hash(ours) {
+		result.Status = UpgradeUpdated
+		return result, afero.WriteFile(gen.fs, path, theirs, 0644)
+	}
+
+	// The generator output didn't change since it was generated, the local changes are kept as they are
+	if gen.manifest.Files[file] == manifest.Hash(theirs) {
+		result.Status = UpgradeUnchanged
+		return result, nil
+	}
+
+	base, err := afero.ReadFile(gen.fs, manifest.BasePath(gen.settings.Path, file))
+	if errors.Is(err, fs.ErrNotExist) {
+		// Without a base there is nothing to merge against, leave the new version next to the file
+		result.Status = UpgradeConflict
+		result.Note = "no base to merge with, new version written to " + file + ".gowizard-new"
+		return result, afero.WriteFile(gen.fs, path+".gowizard-new", theirs, 0644)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	merged, conflicts, err := mergeFile(ctx, ours, base, theirs)
+	if err != nil {
+		return result, err
+	}
+
+	result.Status = UpgradeMerged
+	if conflicts != 0 {
+		result.Status = UpgradeConflict
+		result.Note = fmt.Sprintf("%d conflict(s)", conflicts)
+	}
+
+	return result, afero.WriteFile(gen.fs, path, merged, 0644)
+}
+
+// mergeFile three-way merges two versions of a file with `git merge-file` and returns the number of conflicts
+func mergeFile(ctx context.Context, ours, base, theirs []byte) ([]byte, int, error) {
+	tmpDir, err := os.MkdirTemp("", "gowizard-merge-")
+	if err != nil {
+		return nil, 0, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	paths := make([]string, 3)
+	for i, b := range [][]byte{ours, base, theirs} {
+		paths[i] = filepath.Join(tmpDir, fmt.Sprint(i))
+		err = os.WriteFile(paths[i], b, 0600)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "merge-file", "-p", "-L", "yours", "-L", "base", "-L", "gowizard", paths[0], paths[1], paths[2])
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	out, err := cmd.Output()
+	if err == nil {
+		return out, 0, nil
+	}
+
+	// The exit code is the number of conflicts, negative codes are errors
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 && exitErr.ExitCode() < 128 {
+		return out, exitErr.ExitCode(), nil
+	}
+
+	return nil, 0, fmt.Errorf("git merge-file: %s %s", err, stderr.String())
+}
@@ -0,0 +1,277 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+
+	"github.com/mahcks/gowizard/pkg/manifest"
+)
+
+func TestUpgradeFile(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is needed to merge files")
+	}
+
+	const base = "a\nb\nc\nd\ne\n"
+
+	cases := []struct {
+		name     string
+		ours     string // content of the file in the project, empty when it doesn't exist
+		base     string // content of the base of the file, empty when there is none
+		recorded string // hash of the file in the manifest, empty when it isn't in the manifest
+		theirs   string
+		status   UpgradeStatus
+		expected string // content of the file after the upgrade
+		new      string // content of the .gowizard-new file after the upgrade, empty when there is none
+	}{
+		{
+			name:     "added",
+			theirs:   base,
+			status:   UpgradeAdded,
+			expected: base,
+		},
+		{
+			name:     "deleted locally",
+			base:     base,
+			recorded: manifest.Hash([]byte(base)),
+			theirs:   "a\nb\nc\nd\ne new\n",
+			status:   UpgradeSkipped,
+		},
+		{
+			name:     "same as ours",
+			ours:     "a local\nb\nc\nd\ne\n",
+			base:     base,
+			recorded: manifest.Hash([]byte(base)),
+			theirs:   "a local\nb\nc\nd\ne\n",
+			status:   UpgradeUnchanged,
+			expected: "a local\nb\nc\nd\ne\n",
+		},
+		{
+			name:     "same as base",
+			ours:     "a local\nb\nc\nd\ne\n",
+			base:     base,
+			recorded: manifest.Hash([]byte(base)),
+			theirs:   base,
+			status:   UpgradeUnchanged,
+			expected: "a local\nb\nc\nd\ne\n",
+		},
+		{
+			name:     "untouched",
+			ours:     base,
+			base:     base,
+			recorded: manifest.Hash([]byte(base)),
+			theirs:   "a\nb\nc\nd\ne new\n",
+			status:   UpgradeUpdated,
+			expected: "a\nb\nc\nd\ne new\n",
+		},
+		{
+			name:     "merged",
+			ours:     "a local\nb\nc\nd\ne\n",
+			base:     base,
+			recorded: manifest.Hash([]byte(base)),
+			theirs:   "a\nb\nc\nd\ne new\n",
+			status:   UpgradeMerged,
+			expected: "a local\nb\nc\nd\ne new\n",
+		},
+		{
+			name:     "conflict",
+			ours:     "a\nb\nc local\nd\ne\n",
+			base:     base,
+			recorded: manifest.Hash([]byte(base)),
+			theirs:   "a\nb\nc new\nd\ne\n",
+			status:   UpgradeConflict,
+			expected: "a\nb\n<<<<<<< yours\nc local\n=======\nc new\n>>>>>>> gowizard\nd\ne\n",
+		},
+		{
+			name:     "no base",
+			ours:     "a local\nb\nc\nd\ne\n",
+			recorded: manifest.Hash([]byte(base)),
+			theirs:   "a\nb\nc\nd\ne new\n",
+			status:   UpgradeConflict,
+			expected: "a local\nb\nc\nd\ne\n",
+			new:      "a\nb\nc\nd\ne new\n",
+		},
+	}
+
+	const file = "internal/app/app.go"
+	const path = "/project/" + file
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			gen := NewGenerator(WithFs(fs), WithPath("/project"))
+			gen.manifest = &manifest.Manifest{Files: map[string]string{}}
+
+			if tc.ours != "" {
+				err := afero.WriteFile(fs, path, []byte(tc.ours), 0644)
+				if err != nil {
+					t.Fatalf("error writing %s: %s", file, err)
+				}
+			}
+
+			if tc.base != "" {
+				err := manifest.WriteBase(fs, "/project", file, []byte(tc.base))
+				if err != nil {
+					t.Fatalf("error writing base of %s: %s", file, err)
+				}
+			}
+
+			if tc.recorded != "" {
+				gen.manifest.Files[file] = tc.recorded
+			}
+
+			result, err := gen.upgradeFile(context.Background(), file, []byte(tc.theirs))
+			if err != nil {
+				t.Fatalf("error upgrading %s: %s", file, err)
+			}
+
+			if result.Status != tc.status {
+				t.Errorf("expected %s, got %s (%s)", tc.status, result.Status, result.Note)
+			}
+
+			assertFile(t, fs, path, tc.expected)
+			assertFile(t, fs, path+".gowizard-new", tc.new)
+		})
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	generateProject(t, fs)
+
+	// app.go was changed by hand, config.dev.yaml was deleted and config.yaml is from an older version of gowizard
+	const local = "// Changed by hand\n"
+	src, err := afero.ReadFile(fs, "/project/internal/app/app.go")
+	if err != nil {
+		t.Fatalf("error reading app.go: %s", err)
+	}
+	err = afero.WriteFile(fs, "/project/internal/app/app.go", append(src, local...), 0644)
+	if err != nil {
+		t.Fatalf("error writing app.go: %s", err)
+	}
+
+	err = fs.Remove("/project/config/config.dev.yaml")
+	if err != nil {
+		t.Fatalf("error removing config.dev.yaml: %s", err)
+	}
+
+	m, err := manifest.Load(fs, "/project")
+	if err != nil {
+		t.Fatalf("error loading manifest: %s", err)
+	}
+
+	const old = "old: true\n"
+	err = afero.WriteFile(fs, "/project/config/config.yaml", []byte(old), 0644)
+	if err != nil {
+		t.Fatalf("error writing config.yaml: %s", err)
+	}
+	err = manifest.WriteBase(fs, "/project", "config/config.yaml", []byte(old))
+	if err != nil {
+		t.Fatalf("error writing base of config.yaml: %s", err)
+	}
+	m.Files["config/config.yaml"] = manifest.Hash([]byte(old))
+	err = m.Save(fs, "/project")
+	if err != nil {
+		t.Fatalf("error saving manifest: %s", err)
+	}
+
+	gen := NewGenerator(WithFs(fs), WithDryRun(true))
+	err = gen.LoadProject("/project")
+	if err != nil {
+		t.Fatalf("error loading project: %s", err)
+	}
+
+	results, err := gen.Upgrade(context.Background())
+	if err != nil {
+		t.Fatalf("error upgrading project: %s", err)
+	}
+
+	expected := map[string]UpgradeStatus{
+		"internal/app/app.go":    UpgradeUnchanged,
+		"config/config.dev.yaml": UpgradeSkipped,
+		"config/config.yaml":     UpgradeUpdated,
+		"config/config.go":       UpgradeUnchanged,
+	}
+	for _, result := range results {
+		if status, ok := expected[result.File]; ok && result.Status != status {
+			t.Errorf("expected %s to be %s, got %s", result.File, status, result.Status)
+		}
+	}
+
+	app, err := afero.ReadFile(fs, "/project/internal/app/app.go")
+	if err != nil {
+		t.Fatalf("error reading app.go: %s", err)
+	}
+	if !bytes.HasSuffix(app, []byte(local)) {
+		t.Errorf("expected app.go to keep the local change")
+	}
+
+	config, err := afero.ReadFile(fs, "/project/config/config.yaml")
+	if err != nil {
+		t.Fatalf("error reading config.yaml: %s", err)
+	}
+	base, err := afero.ReadFile(fs, manifest.BasePath("/project", "config/config.yaml"))
+	if err != nil {
+		t.Fatalf("error reading base of config.yaml: %s", err)
+	}
+	if bytes.Equal(config, []byte(old)) || !bytes.Equal(config, base) {
+		t.Errorf("expected config.yaml and its base to be replaced with the generated version:\n%s", config)
+	}
+}
+
+// assertFile fails the test when the content of a file isn't the expected one, empty means that the file doesn't exist
+func assertFile(t *testing.T, fs afero.Fs, path, expected string) {
+	t.Helper()
+
+	b, err := afero.ReadFile(fs, path)
+	if expected == "" {
+		if err == nil {
+			t.Errorf("expected %s not to exist", path)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("error reading %s: %s", path, err)
+	}
+
+	if string(b) != expected {
+		t.Errorf("expected %s to be:\n%s\ngot:\n%s", path, expected, b)
+	}
+}
+
+func TestUpgradeKeepsMigrations(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	generateProject(t, fs, WithAdapters("postgres"), WithServices(map[string]string{}), WithMigrations("postgres"))
+	addAdapter(t, fs, "mariadb")
+
+	gen := NewGenerator(WithFs(fs), WithDryRun(true))
+	err := gen.LoadProject("/project")
+	if err != nil {
+		t.Fatalf("error loading project: %s", err)
+	}
+
+	results, err := gen.Upgrade(context.Background())
+	if err != nil {
+		t.Fatalf("error upgrading project: %s", err)
+	}
+
+	for _, result := range results {
+		if (strings.HasPrefix(result.File, "pkg/migrate/") || strings.HasPrefix(result.File, "migrations/")) && result.Status != UpgradeUnchanged {
+			t.Errorf("expected %s to be unchanged after adding mariadb, got %s", result.File, result.Status)
+		}
+	}
+
+	app, err := afero.ReadFile(fs, "/project/internal/app/app.go")
+	if err != nil {
+		t.Fatalf("error reading app.go: %s", err)
+	}
+	if !bytes.Contains(app, []byte("migrate.Up(cfg.Migrate.Source, cfg.Postgres.URL)")) {
+		t.Errorf("expected the migrations to be applied to postgres:\n%s", app)
+	}
+}
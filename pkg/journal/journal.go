@@ -0,0 +1,278 @@
+package journal
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// Fs wraps a filesystem and records every file and directory that is created or modified through it, so the changes can be undone
+type Fs struct {
+	afero.Fs
+	mu      sync.Mutex
+	entries []entry
+	seen    map[string]bool
+}
+
+// entry is the state of a path before it was first touched
+type entry struct {
+	path    string
+	created bool        // the path didn't exist, undoing removes it
+	dir     bool        // the path was an existing directory, undoing recreates it if it was removed
+	content []byte      // content of an existing file
+	mode    fs.FileMode // mode of an existing file or directory
+}
+
+// NewFs creates a journal on top of a filesystem
+func NewFs(base afero.Fs) *Fs {
+	return &Fs{
+		Fs:   base,
+		seen: map[string]bool{},
+	}
+}
+
+// Record remembers the current state of paths before something outside of the filesystem, i.e. the go tool, changes them
+func (j *Fs) Record(paths ...string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, path := range paths {
+		err := j.record(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// record remembers the state of a path the first time it's touched, missing parent directories are recorded as created
+func (j *Fs) record(path string) error {
+	path = filepath.Clean(path)
+	if j.seen[path] {
+		return nil
+	}
+
+	info, err := j.Fs.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		// Parents first, so they are removed after everything in them
+		parent := filepath.Dir(path)
+		if parent != path {
+			err = j.record(parent)
+			if err != nil {
+				return err
+			}
+		}
+
+		j.seen[path] = true
+		j.entries = append(j.entries, entry{path: path, created: true})
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	j.seen[path] = true
+	if info.IsDir() {
+		j.entries = append(j.entries, entry{path: path, dir: true, mode: info.Mode().Perm()})
+		return nil
+	}
+
+	content, err := afero.ReadFile(j.Fs, path)
+	if err != nil {
+		return err
+	}
+
+	j.entries = append(j.entries, entry{path: path, content: content, mode: info.Mode().Perm()})
+	return nil
+}
+
+// recordTree records a path and everything below it before it is removed
+func (j *Fs) recordTree(path string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	err := afero.Walk(j.Fs, path, func(p string, info os.FileInfo, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && p == path {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		return j.record(p)
+	})
+	if err != nil {
+		return err
+	}
+
+	return j.record(path)
+}
+
+// Rollback undoes every change, created paths are removed and modified files and directories are restored
+// Parents are recorded before their children, so created paths are removed from the back and the rest is restored from the front
+// The journal is empty afterwards
+func (j *Fs) Rollback() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var errs []error
+	for i := len(j.entries) - 1; i >= 0; i-- {
+		if e := j.entries[i]; e.created {
+			err := j.Fs.RemoveAll(e.path)
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	for _, e := range j.entries {
+		switch {
+		case e.created:
+			continue
+		case e.dir:
+			// The directory may have been removed or replaced by a file
+			var err error
+			if info, statErr := j.Fs.Stat(e.path); statErr == nil && !info.IsDir() {
+				err = j.Fs.Remove(e.path)
+			}
+			if err == nil {
+				err = j.Fs.MkdirAll(e.path, e.mode)
+			}
+			if err == nil {
+				err = j.Fs.Chmod(e.path, e.mode)
+			}
+			if err != nil {
+				errs = append(errs, err)
+			}
+		default:
+			// The file may have been removed or replaced by a directory
+			err := j.Fs.RemoveAll(e.path)
+			if err == nil {
+				err = j.Fs.MkdirAll(filepath.Dir(e.path), 0755)
+			}
+			if err == nil {
+				err = afero.WriteFile(j.Fs, e.path, e.content, e.mode)
+			}
+			if err == nil {
+				err = j.Fs.Chmod(e.path, e.mode)
+			}
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	j.entries = nil
+	j.seen = map[string]bool{}
+
+	return errors.Join(errs...)
+}
+
+func (j *Fs) Create(name string) (afero.File, error) {
+	err := j.Record(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return j.Fs.Create(name)
+}
+
+func (j *Fs) Mkdir(name string, perm os.FileMode) error {
+	err := j.Record(name)
+	if err != nil {
+		return err
+	}
+
+	return j.Fs.Mkdir(name, perm)
+}
+
+func (j *Fs) MkdirAll(path string, perm os.FileMode) error {
+	err := j.Record(path)
+	if err != nil {
+		return err
+	}
+
+	return j.Fs.MkdirAll(path, perm)
+}
+
+func (j *Fs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	// Only files that are opened for writing can change
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND) != 0 {
+		err := j.Record(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return j.Fs.OpenFile(name, flag, perm)
+}
+
+func (j *Fs) Remove(name string) error {
+	err := j.recordTree(name)
+	if err != nil {
+		return err
+	}
+
+	return j.Fs.Remove(name)
+}
+
+func (j *Fs) RemoveAll(path string) error {
+	err := j.recordTree(path)
+	if err != nil {
+		return err
+	}
+
+	return j.Fs.RemoveAll(path)
+}
+
+func (j *Fs) Rename(oldname, newname string) error {
+	err := j.recordTree(oldname)
+	if err != nil {
+		return err
+	}
+
+	err = j.recordTree(newname)
+	if err != nil {
+		return err
+	}
+
+	return j.Fs.Rename(oldname, newname)
+}
+
+func (j *Fs) Chmod(name string, mode os.FileMode) error {
+	err := j.Record(name)
+	if err != nil {
+		return err
+	}
+
+	return j.Fs.Chmod(name, mode)
+}
+
+func (j *Fs) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	err := j.Record(name)
+	if err != nil {
+		return err
+	}
+
+	return j.Fs.Chtimes(name, atime, mtime)
+}
+
+func (j *Fs) Chown(name string, uid, gid int) error {
+	err := j.Record(name)
+	if err != nil {
+		return err
+	}
+
+	return j.Fs.Chown(name, uid, gid)
+}
+
+// Name returns the name of the filesystem
+func (j *Fs) Name() string {
+	return "journal(" + j.Fs.Name() + ")"
+}
@@ -0,0 +1,236 @@
+package journal
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+// snapshot returns every path below root with its mode and the content of files
+func snapshot(t *testing.T, fsys afero.Fs, root string) map[string]string {
+	t.Helper()
+
+	tree := map[string]string{}
+	err := afero.Walk(fsys, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			tree[path] = info.Mode().String()
+			return nil
+		}
+
+		content, err := afero.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+
+		tree[path] = fmt.Sprintf("%s %q", info.Mode(), content)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("error walking %s: %s", root, err)
+	}
+
+	return tree
+}
+
+// writeFiles writes files and creates their parents with their mode
+func writeFiles(t *testing.T, fsys afero.Fs, files map[string]string, dirs map[string]os.FileMode) {
+	t.Helper()
+
+	for dir, mode := range dirs {
+		err := fsys.MkdirAll(dir, mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = fsys.Chmod(dir, mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for file, content := range files {
+		err := afero.WriteFile(fsys, file, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRecordParentsFirst(t *testing.T) {
+	base := afero.NewMemMapFs()
+	writeFiles(t, base, nil, map[string]os.FileMode{"/project": 0755})
+
+	j := NewFs(base)
+	err := j.MkdirAll("/project/a/b", 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = afero.WriteFile(j, "/project/a/b/c.txt", []byte("c"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The existing parent is recorded as it is, the missing ones as created
+	var paths []string
+	for _, e := range j.entries {
+		if e.created == (e.path == "/project") {
+			t.Errorf("expected only the missing paths to be recorded as created, got %+v", e)
+		}
+		paths = append(paths, e.path)
+	}
+
+	expected := []string{"/project", "/project/a", "/project/a/b", "/project/a/b/c.txt"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected the entries %q, got %q", expected, paths)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	cases := []struct {
+		name   string
+		change func(t *testing.T, j *Fs)
+	}{
+		{
+			name: "create files",
+			change: func(t *testing.T, j *Fs) {
+				writeFiles(t, j, map[string]string{
+					"/project/new.txt":           "new",
+					"/project/new/deep/file.txt": "deep",
+					"/project/dir/sub/added.txt": "added",
+				}, map[string]os.FileMode{"/project/new/deep": 0755})
+			},
+		},
+		{
+			name: "modify files",
+			change: func(t *testing.T, j *Fs) {
+				writeFiles(t, j, map[string]string{
+					"/project/edit.txt":     "changed",
+					"/project/dir/sub/a.go": "package changed",
+				}, nil)
+
+				err := j.Chmod("/project/edit.txt", 0755)
+				if err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "remove trees",
+			change: func(t *testing.T, j *Fs) {
+				err := j.RemoveAll("/project/dir")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				err = j.Remove("/project/empty")
+				if err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "replace a removed tree",
+			change: func(t *testing.T, j *Fs) {
+				err := j.RemoveAll("/project/dir")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// A file where there was a directory and a directory where there was a file
+				writeFiles(t, j, map[string]string{
+					"/project/dir":          "file",
+					"/project/edit.txt/new": "dir",
+				}, nil)
+			},
+		},
+		{
+			name: "rename",
+			change: func(t *testing.T, j *Fs) {
+				err := j.Rename("/project/keep.txt", "/project/dir/sub/moved.txt")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				err = j.Rename("/project/dir/b.txt", "/project/edit.txt")
+				if err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "recorded paths",
+			change: func(t *testing.T, j *Fs) {
+				// The go tool writes go.mod and go.sum without going through the journal
+				err := j.Record("/project/go.mod", "/project/go.sum")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				writeFiles(t, j.Fs, map[string]string{
+					"/project/go.mod": "module changed",
+					"/project/go.sum": "sum",
+				}, nil)
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			base := afero.NewMemMapFs()
+			writeFiles(t, base, map[string]string{
+				"/project/go.mod":       "module project",
+				"/project/keep.txt":     "keep",
+				"/project/edit.txt":     "edit",
+				"/project/dir/b.txt":    "b",
+				"/project/dir/sub/a.go": "package sub",
+			}, map[string]os.FileMode{
+				"/project":         0755,
+				"/project/dir/sub": 0700,
+				"/project/empty":   0750,
+			})
+			err := base.Chmod("/project/edit.txt", 0600)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			before := snapshot(t, base, "/")
+
+			j := NewFs(base)
+			tc.change(t, j)
+
+			if reflect.DeepEqual(before, snapshot(t, base, "/")) {
+				t.Fatal("expected the change to modify the tree")
+			}
+
+			err = j.Rollback()
+			if err != nil {
+				t.Fatalf("error rolling back: %s", err)
+			}
+
+			after := snapshot(t, base, "/")
+			for path, state := range before {
+				if after[path] != state {
+					t.Errorf("expected %s to be restored to %s, got %q", path, state, after[path])
+				}
+			}
+			for path := range after {
+				if _, ok := before[path]; !ok {
+					t.Errorf("expected %s to be removed", path)
+				}
+			}
+
+			if len(j.entries) != 0 || len(j.seen) != 0 {
+				t.Errorf("expected the journal to be empty after a rollback")
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package loggers
+
+import (
+	j "github.com/dave/jennifer/jen"
+	"github.com/spf13/afero"
+
+	"github.com/mahcks/gowizard/pkg/domain"
+	"github.com/mahcks/gowizard/pkg/utils"
+)
+
+// Every logger generates the same package, the rest of the project only uses Interface and New:
+//
+//	l := logger.New(cfg.Logger.Level, cfg.Logger.Format)
+//	l.Error("error connecting to redis", "error", err)
+
+// configYAML is the logger section of the config, info and above is logged as text
+func configYAML() map[string]interface{} {
+	return map[string]interface{}{
+		"logger": map[string]interface{}{
+			"level":  "info",
+			"format": "text",
+		},
+	}
+}
+
+// configGo is the logger section of the config struct
+func configGo() *j.Statement {
+	return j.Id("Logger").Struct(
+		j.Id("Level").String().Tag(map[string]string{"mapstructure": "level", "json": "level"}),
+		j.Id("Format").String().Tag(map[string]string{"mapstructure": "format", "json": "format"}),
+	).Tag(map[string]string{"mapstructure": "logger", "json": "logger"})
+}
+
+// newFile creates logger.go with the Interface the generated logger implements
+func newFile(ns domain.Namespace) *j.File {
+	f := j.NewFilePathName(ns.Path(), ns.PackageName())
+
+	f.Comment("Interface - Logs a message with key-value pairs, i.e. l.Error(\"error connecting to redis\", \"error\", err)")
+	f.Type().Id("Interface").InterfaceFunc(func(g *j.Group) {
+		for _, level := range []string{"Debug", "Info", "Warn", "Error"} {
+			g.Id(level).Params(j.Id("msg").String(), j.Id("args").Op("...").Any())
+		}
+	})
+
+	f.Line()
+
+	return f
+}
+
+// addMethods adds the Interface methods to the Logger type of the file, call returns the statement a method logs with
+func addMethods(f *j.File, call func(level string) *j.Statement) {
+	for _, level := range []string{"Debug", "Info", "Warn", "Error"} {
+		f.Func().Params(j.Id("l").Add(utils.Jptr).Id("Logger")).Id(level).Params(j.Id("msg").String(), j.Id("args").Op("...").Any()).Block(
+			call(level),
+		)
+
+		f.Line()
+	}
+}
+
+// save writes the file to logger.go in the package of the namespace
+func save(fs afero.Fs, f *j.File, name string, ns domain.Namespace, path string) error {
+	err := utils.SaveFile(fs, f, path+"/"+ns.Package+"/logger.go")
+	if err != nil {
+		return &domain.GenerateError{Module: name, File: ns.Package + "/logger.go", Err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package loggers
+
+import (
+	j "github.com/dave/jennifer/jen"
+	"github.com/spf13/afero"
+
+	"github.com/mahcks/gowizard/pkg/domain"
+	"github.com/mahcks/gowizard/pkg/utils"
+)
+
+type SlogLogger struct {
+	name        string // name of the logger
+	displayName string // name of the logger that will be displayed in the CLI
+}
+
+// GetName returns the name of the logger
+func (lgr *SlogLogger) GetName() string {
+	return lgr.name
+}
+
+// GetDisplayName - what will be displayed in the CLI when prompted
+func (lgr *SlogLogger) GetDisplayName() string {
+	return lgr.displayName
+}
+
+func NewSlogLogger() domain.ModuleI {
+	return &SlogLogger{
+		name:        "slog",
+		displayName: "log/slog",
+	}
+}
+
+// ConfigYAML is the configuration of the logger in YAML format
+func (lgr *SlogLogger) ConfigYAML() map[string]interface{} {
+	return configYAML()
+}
+
+// ConfigGo is the configuration of the logger in Go format
+func (lgr *SlogLogger) ConfigGo() *j.Statement {
+	return configGo()
+}
+
+// AppInit - the logger is created in main.go and passed to Run
+func (lgr *SlogLogger) AppInit(ns domain.Namespace) []j.Code {
+	return nil
+}
+
+func (lgr *SlogLogger) AppSelect(ns domain.Namespace) j.Code {
+	return nil
+}
+
+func (lgr *SlogLogger) AppShutdown(ns domain.Namespace) []j.Code {
+	return nil
+}
+
+// Constraints - what the logger needs from the rest of the project
+func (lgr *SlogLogger) Constraints() domain.Constraints {
+	return domain.Constraints{
+		GoVersion: "1.21", // log/slog was added to the standard library
+		Packages:  []string{"pkg/logger"},
+	}
+}
+
+// Service is the code that will be added to the package of its namespace, *slog.Logger already implements Interface
+func (lgr *SlogLogger) Service(fs afero.Fs, ns domain.Namespace, path string) error {
+	f := newFile(ns)
+
+	f.Comment("New - Logs to stdout at the level (debug, info, warn or error) in the format (json or text)")
+	f.Comment("An unknown level logs info and above, an unknown format logs text")
+	f.Func().Id("New").Params(j.List(j.Id("level"), j.Id("format")).String()).Id("Interface").Block(
+		j.Id("opts").Op(":=").Add(utils.Rptr).Qual("log/slog", "HandlerOptions").Values(j.Dict{
+			j.Id("Level"): j.Id("parseLevel").Call(j.Id("level")),
+		}),
+		j.Line(),
+		j.If(j.Id("format").Op("==").Lit("json")).Block(
+			j.Return(j.Qual("log/slog", "New").Call(j.Qual("log/slog", "NewJSONHandler").Call(j.Qual("os", "Stdout"), j.Id("opts")))),
+		),
+		j.Line(),
+		j.Return(j.Qual("log/slog", "New").Call(j.Qual("log/slog", "NewTextHandler").Call(j.Qual("os", "Stdout"), j.Id("opts")))),
+	)
+
+	f.Line()
+
+	f.Func().Id("parseLevel").Params(j.Id("level").String()).Qual("log/slog", "Level").Block(
+		j.Var().Id("l").Qual("log/slog", "Level"),
+		j.Err().Op(":=").Id("l").Dot("UnmarshalText").Call(j.Index().Byte().Call(j.Id("level"))),
+		j.If(j.Err().Op("!=").Nil()).Block(
+			j.Return(j.Qual("log/slog", "LevelInfo")),
+		),
+		j.Line(),
+		j.Return(j.Id("l")),
+	)
+
+	return save(fs, f, lgr.name, ns, path)
+}
@@ -0,0 +1,104 @@
+package loggers
+
+import (
+	j "github.com/dave/jennifer/jen"
+	"github.com/spf13/afero"
+
+	"github.com/mahcks/gowizard/pkg/domain"
+	"github.com/mahcks/gowizard/pkg/utils"
+)
+
+type ZapLogger struct {
+	name        string // name of the logger
+	displayName string // name of the logger that will be displayed in the CLI
+}
+
+// GetName returns the name of the logger
+func (lgr *ZapLogger) GetName() string {
+	return lgr.name
+}
+
+// GetDisplayName - what will be displayed in the CLI when prompted
+func (lgr *ZapLogger) GetDisplayName() string {
+	return lgr.displayName
+}
+
+func NewZapLogger() domain.ModuleI {
+	return &ZapLogger{
+		name:        "zap",
+		displayName: "go.uber.org/zap",
+	}
+}
+
+// ConfigYAML is the configuration of the logger in YAML format
+func (lgr *ZapLogger) ConfigYAML() map[string]interface{} {
+	return configYAML()
+}
+
+// ConfigGo is the configuration of the logger in Go format
+func (lgr *ZapLogger) ConfigGo() *j.Statement {
+	return configGo()
+}
+
+// AppInit - the logger is created in main.go and passed to Run
+func (lgr *ZapLogger) AppInit(ns domain.Namespace) []j.Code {
+	return nil
+}
+
+func (lgr *ZapLogger) AppSelect(ns domain.Namespace) j.Code {
+	return nil
+}
+
+func (lgr *ZapLogger) AppShutdown(ns domain.Namespace) []j.Code {
+	return nil
+}
+
+// Constraints - what the logger needs from the rest of the project
+func (lgr *ZapLogger) Constraints() domain.Constraints {
+	return domain.Constraints{
+		Packages: []string{"pkg/logger"},
+	}
+}
+
+// Service is the code that will be added to the package of its namespace, the key-value pairs are logged with the sugared logger
+func (lgr *ZapLogger) Service(fs afero.Fs, ns domain.Namespace, path string) error {
+	f := newFile(ns)
+
+	f.Comment("Logger - Implements Interface with a sugared zap logger")
+	f.Type().Id("Logger").Struct(
+		j.Id("sugar").Add(utils.Jptr).Qual("go.uber.org/zap", "SugaredLogger"),
+	)
+
+	f.Line()
+
+	f.Comment("New - Logs to stdout at the level (debug, info, warn or error) in the format (json or text)")
+	f.Comment("An unknown level logs info and above, an unknown format logs text")
+	f.Func().Id("New").Params(j.List(j.Id("level"), j.Id("format")).String()).Id("Interface").Block(
+		j.List(j.Id("lvl"), j.Err()).Op(":=").Qual("go.uber.org/zap/zapcore", "ParseLevel").Call(j.Id("level")),
+		j.If(j.Err().Op("!=").Nil()).Block(
+			j.Id("lvl").Op("=").Qual("go.uber.org/zap/zapcore", "InfoLevel"),
+		),
+		j.Line(),
+		j.Id("encoderConfig").Op(":=").Qual("go.uber.org/zap", "NewProductionEncoderConfig").Call(),
+		j.Id("encoderConfig").Dot("EncodeTime").Op("=").Qual("go.uber.org/zap/zapcore", "ISO8601TimeEncoder"),
+		j.Line(),
+		j.Id("encoder").Op(":=").Qual("go.uber.org/zap/zapcore", "NewConsoleEncoder").Call(j.Id("encoderConfig")),
+		j.If(j.Id("format").Op("==").Lit("json")).Block(
+			j.Id("encoder").Op("=").Qual("go.uber.org/zap/zapcore", "NewJSONEncoder").Call(j.Id("encoderConfig")),
+		),
+		j.Line(),
+		j.Id("core").Op(":=").Qual("go.uber.org/zap/zapcore", "NewCore").Call(j.Id("encoder"), j.Qual("go.uber.org/zap/zapcore", "Lock").Call(j.Qual("os", "Stdout")), j.Id("lvl")),
+		j.Line(),
+		j.Return(j.Add(utils.Rptr).Id("Logger").Values(j.Dict{
+			j.Id("sugar"): j.Qual("go.uber.org/zap", "New").Call(j.Id("core")).Dot("Sugar").Call(),
+		})),
+	)
+
+	f.Line()
+
+	addMethods(f, func(level string) *j.Statement {
+		return j.Id("l").Dot("sugar").Dot(level+"w").Call(j.Id("msg"), j.Id("args").Op("..."))
+	})
+
+	return save(fs, f, lgr.name, ns, path)
+}
@@ -0,0 +1,102 @@
+package loggers
+
+import (
+	j "github.com/dave/jennifer/jen"
+	"github.com/spf13/afero"
+
+	"github.com/mahcks/gowizard/pkg/domain"
+	"github.com/mahcks/gowizard/pkg/utils"
+)
+
+type ZerologLogger struct {
+	name        string // name of the logger
+	displayName string // name of the logger that will be displayed in the CLI
+}
+
+// GetName returns the name of the logger
+func (lgr *ZerologLogger) GetName() string {
+	return lgr.name
+}
+
+// GetDisplayName - what will be displayed in the CLI when prompted
+func (lgr *ZerologLogger) GetDisplayName() string {
+	return lgr.displayName
+}
+
+func NewZerologLogger() domain.ModuleI {
+	return &ZerologLogger{
+		name:        "zerolog",
+		displayName: "github.com/rs/zerolog",
+	}
+}
+
+// ConfigYAML is the configuration of the logger in YAML format
+func (lgr *ZerologLogger) ConfigYAML() map[string]interface{} {
+	return configYAML()
+}
+
+// ConfigGo is the configuration of the logger in Go format
+func (lgr *ZerologLogger) ConfigGo() *j.Statement {
+	return configGo()
+}
+
+// AppInit - the logger is created in main.go and passed to Run
+func (lgr *ZerologLogger) AppInit(ns domain.Namespace) []j.Code {
+	return nil
+}
+
+func (lgr *ZerologLogger) AppSelect(ns domain.Namespace) j.Code {
+	return nil
+}
+
+func (lgr *ZerologLogger) AppShutdown(ns domain.Namespace) []j.Code {
+	return nil
+}
+
+// Constraints - what the logger needs from the rest of the project
+func (lgr *ZerologLogger) Constraints() domain.Constraints {
+	return domain.Constraints{
+		Packages: []string{"pkg/logger"},
+	}
+}
+
+// Service is the code that will be added to the package of its namespace, the key-value pairs are added as fields of the event
+func (lgr *ZerologLogger) Service(fs afero.Fs, ns domain.Namespace, path string) error {
+	f := newFile(ns)
+
+	f.Comment("Logger - Implements Interface with a zerolog logger")
+	f.Type().Id("Logger").Struct(
+		j.Id("logger").Qual("github.com/rs/zerolog", "Logger"),
+	)
+
+	f.Line()
+
+	f.Comment("New - Logs to stdout at the level (debug, info, warn or error) in the format (json or text)")
+	f.Comment("An unknown level logs info and above, an unknown format logs text")
+	f.Func().Id("New").Params(j.List(j.Id("level"), j.Id("format")).String()).Id("Interface").Block(
+		j.List(j.Id("lvl"), j.Err()).Op(":=").Qual("github.com/rs/zerolog", "ParseLevel").Call(j.Id("level")),
+		j.If(j.Err().Op("!=").Nil().Op("||").Id("lvl").Op("==").Qual("github.com/rs/zerolog", "NoLevel")).Block(
+			j.Id("lvl").Op("=").Qual("github.com/rs/zerolog", "InfoLevel"),
+		),
+		j.Line(),
+		j.Var().Id("w").Qual("io", "Writer").Op("=").Qual("github.com/rs/zerolog", "ConsoleWriter").Values(j.Dict{
+			j.Id("Out"):        j.Qual("os", "Stdout"),
+			j.Id("TimeFormat"): j.Qual("time", "RFC3339"),
+		}),
+		j.If(j.Id("format").Op("==").Lit("json")).Block(
+			j.Id("w").Op("=").Qual("os", "Stdout"),
+		),
+		j.Line(),
+		j.Return(j.Add(utils.Rptr).Id("Logger").Values(j.Dict{
+			j.Id("logger"): j.Qual("github.com/rs/zerolog", "New").Call(j.Id("w")).Dot("Level").Call(j.Id("lvl")).Dot("With").Call().Dot("Timestamp").Call().Dot("Logger").Call(),
+		})),
+	)
+
+	f.Line()
+
+	addMethods(f, func(level string) *j.Statement {
+		return j.Id("l").Dot("logger").Dot(level).Call().Dot("Fields").Call(j.Id("args")).Dot("Msg").Call(j.Id("msg"))
+	})
+
+	return save(fs, f, lgr.name, ns, path)
+}
@@ -0,0 +1,136 @@
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/mahcks/gowizard/pkg/domain"
+	"github.com/spf13/afero"
+)
+
+// Filename is the name of the manifest file that is written into the root of every generated project
+const Filename = ".gowizard.lock"
+
+// BaseDir is where the generated content of every file is kept, it is the base of the three-way merge on upgrade
+const BaseDir = ".gowizard/base"
+
+// ErrNotFound is returned when a project has no manifest
+var ErrNotFound = errors.New("no " + Filename + " found, was the project generated by gowizard?")
+
+// Manifest records what gowizard generated so later changes can be told apart from what humans changed
+type Manifest struct {
+	Version  string            `json:"version"`            // Version of gowizard that generated the project
+	Settings domain.Settings   `json:"settings"`           // Settings the project was generated with
+	Template *Template         `json:"template,omitempty"` // Template the project was cloned from, if any
+	Files    map[string]string `json:"files"`              // Content hash of every generated file, keyed by its slash separated path relative to the project
+}
+
+// Template is the template a project was generated from
+type Template struct {
+	Name   string `json:"name"`             // Name of the template repository, i.e. github.com/evrone/go-clean-template
+	Commit string `json:"commit,omitempty"` // Commit of the template that was cloned
+	Custom bool   `json:"custom,omitempty"` // Custom templates don't have a setup step
+}
+
+// Load reads the manifest from the root of a project
+func Load(fsys afero.Fs, projectPath string) (*Manifest, error) {
+	b, err := afero.ReadFile(fsys, filepath.Join(projectPath, Filename))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{}
+	err = json.Unmarshal(b, m)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %s", Filename, err)
+	}
+
+	if m.Files == nil {
+		m.Files = map[string]string{}
+	}
+
+	return m, nil
+}
+
+// Save writes the manifest to the root of a project
+func (m *Manifest) Save(fsys afero.Fs, projectPath string) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return afero.WriteFile(fsys, filepath.Join(projectPath, Filename), append(b, '\n'), 0644)
+}
+
+// Hash returns the content hash of a file as it is stored in the manifest
+func Hash(b []byte) string {
+	sum := sha256.Sum256(b)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// HashFiles hashes every file in a project, the manifest itself, the .gowizard and .git folders are skipped
+func HashFiles(fsys afero.Fs, projectPath string) (map[string]string, error) {
+	files := map[string]string{}
+
+	err := afero.Walk(fsys, projectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if info.Name() == ".git" || path == filepath.Join(projectPath, ".gowizard") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(projectPath, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if rel == Filename {
+			return nil
+		}
+
+		b, err := afero.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+
+		files[rel] = Hash(b)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// BasePath returns where the generated content of a file is kept
+func BasePath(projectPath, file string) string {
+	return filepath.Join(projectPath, BaseDir, filepath.FromSlash(file))
+}
+
+// WriteBase keeps the generated content of a file so it can be used as the base of a three-way merge
+func WriteBase(fsys afero.Fs, projectPath, file string, b []byte) error {
+	path := BasePath(projectPath, file)
+
+	err := fsys.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	return afero.WriteFile(fsys, path, b, 0644)
+}
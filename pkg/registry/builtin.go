@@ -0,0 +1,34 @@
+package registry
+
+import (
+	adapterTemplates "github.com/mahcks/gowizard/pkg/adapters"
+	loggerTemplates "github.com/mahcks/gowizard/pkg/loggers"
+	serviceTemplates "github.com/mahcks/gowizard/pkg/services"
+	repoTemplates "github.com/mahcks/gowizard/pkg/templates"
+)
+
+// Built-in adapters, services, loggers and templates, packages that import the registry can rely on them being registered
+func init() {
+	// Register adapters here
+	RegisterAdapter(adapterTemplates.NewMariaDBAdapter())
+	RegisterAdapter(adapterTemplates.NewMongoDBAdapter())
+	RegisterAdapter(adapterTemplates.NewPostgresAdapter())
+	RegisterAdapter(adapterTemplates.NewRedisAdapter())
+	RegisterAdapter(adapterTemplates.NewSQLAdapter())
+
+	// Register services
+	RegisterService(serviceTemplates.NewRESTService())
+	RegisterService(serviceTemplates.NewGQLService())
+	RegisterService(serviceTemplates.NewGRPCService())
+
+	// Register loggers
+	RegisterLogger(loggerTemplates.NewSlogLogger())
+	RegisterLogger(loggerTemplates.NewZapLogger())
+	RegisterLogger(loggerTemplates.NewZerologLogger())
+
+	// Register templates here
+	RegisterTemplate(repoTemplates.NewGoBackendCleanArchitectureTemplateRepo())
+	RegisterTemplate(repoTemplates.NewGoCleanArchTemplateRepo())
+	RegisterTemplate(repoTemplates.NewGoCleanTemplateRepo())
+	RegisterTemplate(repoTemplates.NewGoCoffeshopRepo())
+}
@@ -0,0 +1,155 @@
+package registry
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/mahcks/gowizard/pkg/domain"
+)
+
+// The built-in adapters, services, loggers and templates are registered in builtin.go
+var (
+	mu        sync.RWMutex
+	adapters  = map[string]domain.ModuleI{}
+	loggers   = map[string]domain.ModuleI{}
+	services  = map[string]domain.ServiceI{}
+	templates = map[string]domain.TemplateI{}
+)
+
+// RegisterAdapter - Makes an adapter available to the generator, usually called from the init function of its package
+// It panics if an adapter with the same name is already registered
+func RegisterAdapter(adapter domain.ModuleI) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if adapter == nil {
+		panic("registry: adapter is nil")
+	}
+
+	if _, ok := adapters[adapter.GetName()]; ok {
+		panic(fmt.Sprintf("registry: adapter %s is registered twice", adapter.GetName()))
+	}
+
+	adapters[adapter.GetName()] = adapter
+}
+
+// RegisterService - Makes a service and its flavors available to the generator
+// It panics if a service with the same name is already registered
+func RegisterService(service domain.ServiceI) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if service == nil {
+		panic("registry: service is nil")
+	}
+
+	if _, ok := services[service.GetName()]; ok {
+		panic(fmt.Sprintf("registry: service %s is registered twice", service.GetName()))
+	}
+
+	services[service.GetName()] = service
+}
+
+// RegisterFlavor - Adds a flavor to an already registered service, i.e. another REST framework
+// The flavor is added to the map returned by the GetFlavors method of the service.
+// It panics if the service isn't registered or already has a flavor with the same name
+func RegisterFlavor(service string, flavor domain.FlavorI) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if flavor == nil {
+		panic("registry: flavor is nil")
+	}
+
+	svc, ok := services[service]
+	if !ok {
+		panic(fmt.Sprintf("registry: can't register flavor %s, service %s isn't registered", flavor.GetName(), service))
+	}
+
+	flavors := svc.GetFlavors()
+	if flavors == nil {
+		panic(fmt.Sprintf("registry: service %s has no flavors map", service))
+	}
+
+	if _, ok := flavors[flavor.GetName()]; ok {
+		panic(fmt.Sprintf("registry: flavor %s of service %s is registered twice", flavor.GetName(), service))
+	}
+
+	flavors[flavor.GetName()] = flavor
+}
+
+// RegisterLogger - Makes a logger available to the generator, it generates the logger package of the project
+// It panics if a logger with the same name is already registered
+func RegisterLogger(logger domain.ModuleI) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if logger == nil {
+		panic("registry: logger is nil")
+	}
+
+	if _, ok := loggers[logger.GetName()]; ok {
+		panic(fmt.Sprintf("registry: logger %s is registered twice", logger.GetName()))
+	}
+
+	loggers[logger.GetName()] = logger
+}
+
+// RegisterTemplate - Makes a template available to the generator, it's keyed by its name, i.e. github.com/evrone/go-clean-template
+// It panics if a template with the same name is already registered
+func RegisterTemplate(template domain.TemplateI) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if template == nil {
+		panic("registry: template is nil")
+	}
+
+	if _, ok := templates[template.GetName()]; ok {
+		panic(fmt.Sprintf("registry: template %s is registered twice", template.GetName()))
+	}
+
+	templates[template.GetName()] = template
+}
+
+// Adapters - Returns every registered adapter keyed by its name
+func Adapters() map[string]domain.ModuleI {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return clone(adapters)
+}
+
+// Services - Returns every registered service keyed by its name
+func Services() map[string]domain.ServiceI {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return clone(services)
+}
+
+// Loggers - Returns every registered logger keyed by its name
+func Loggers() map[string]domain.ModuleI {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return clone(loggers)
+}
+
+// Templates - Returns every registered template keyed by its name
+func Templates() map[string]domain.TemplateI {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return clone(templates)
+}
+
+// clone copies a map so the registry can't be changed through it
+func clone[V any](m map[string]V) map[string]V {
+	c := make(map[string]V, len(m))
+	for key, value := range m {
+		c[key] = value
+	}
+
+	return c
+}
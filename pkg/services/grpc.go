@@ -0,0 +1,42 @@
+package services
+
+import (
+	"github.com/mahcks/gowizard/pkg/domain"
+	flavors "github.com/mahcks/gowizard/pkg/flavors/grpc"
+)
+
+type GRPCService struct {
+	name        string // name of the service
+	displayName string // name of the adapter that will be displayed in the CLI
+	flavors     map[string]domain.FlavorI
+}
+
+func NewGRPCService() domain.ServiceI {
+	return &GRPCService{
+		name:        "grpc",
+		displayName: "gRPC",
+		flavors: map[string]domain.FlavorI{
+			"grpc-go": flavors.NewGRPCGoFlavor(),
+		},
+	}
+}
+
+// GetName returns the name of the service
+func (svc *GRPCService) GetName() string {
+	return svc.name
+}
+
+// GetDisplayName - what will be displayed in the CLI when prompted
+func (svc *GRPCService) GetDisplayName() string {
+	return svc.displayName
+}
+
+// GetFlavors - returns the flavors that are available for this service
+func (svc *GRPCService) GetFlavors() map[string]domain.FlavorI {
+	return svc.flavors
+}
+
+// GetFlavor - returns the flavor that is available for this service
+func (svc *GRPCService) GetFlavor(flavor string) domain.FlavorI {
+	return svc.flavors[flavor]
+}
@@ -17,9 +17,12 @@ func NewRESTService() domain.ServiceI {
 		displayName: "REST",
 		flavors: map[string]domain.FlavorI{
 			"beego":    flavors.NewBeegoFlavor(),
+			"chi":      flavors.NewChiFlavor(),
+			"echo":     flavors.NewEchoFlavor(),
 			"fasthttp": flavors.NewFastHTTPFlavor(),
 			"fiber":    flavors.NewFiberFlavor(),
 			"gin":      flavors.NewGinFlavor(),
+			"stdlib":   flavors.NewStdlibFlavor(),
 		},
 	}
 }
@@ -0,0 +1,101 @@
+package spec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Spec is a declarative description of a project that can be generated with `gowizard generate --from`
+type Spec struct {
+	Module     string            `yaml:"module" json:"module"`                             // Module name
+	GoVersion  string            `yaml:"go_version,omitempty" json:"go_version,omitempty"` // Go module version
+	Path       string            `yaml:"path,omitempty" json:"path,omitempty"`             // Path to the module
+	Adapters   []string          `yaml:"adapters,omitempty" json:"adapters,omitempty"`     // Enabled adapters
+	Services   map[string]string `yaml:"services,omitempty" json:"services,omitempty"`     // Enabled services, key is the service name, value is the flavor name
+	Logger     string            `yaml:"logger,omitempty" json:"logger,omitempty"`         // Logger of the project, i.e. slog, none or leaving it out generates the project without one
+	Migrations string            `yaml:"migrations,omitempty" json:"migrations,omitempty"` // Adapter of the SQL database to scaffold migrations for, i.e. postgres
+	Template   string            `yaml:"template,omitempty" json:"template,omitempty"`     // Template to generate the project from
+	Options    Options           `yaml:"options,omitempty" json:"options,omitempty"`       // Extra options for the generator
+}
+
+// Options are the optional settings of a spec
+type Options struct {
+	CustomTemplate bool `yaml:"custom_template,omitempty" json:"custom_template,omitempty"` // Template is a custom repository and has no setup step
+}
+
+// Load reads a spec from a YAML or JSON file, the format is picked by the file extension
+func Load(path string) (*Spec, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Spec{}
+	if isJSON(path) {
+		err = json.Unmarshal(b, s)
+	} else {
+		err = yaml.Unmarshal(b, s)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing spec %s: %s", path, err)
+	}
+
+	return s, nil
+}
+
+// Save writes the spec to a YAML or JSON file, the format is picked by the file extension
+func (s *Spec) Save(path string) error {
+	b, err := s.Marshal(path)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, b, 0644)
+}
+
+// Marshal encodes the spec as the content of a YAML or JSON file, the format is picked by the extension of path
+func (s *Spec) Marshal(path string) ([]byte, error) {
+	if isJSON(path) {
+		b, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		return append(b, '\n'), nil
+	}
+
+	return yaml.Marshal(s)
+}
+
+// Validate checks that the spec has everything needed to generate a project
+func (s *Spec) Validate() error {
+	if s.Module == "" {
+		return errors.New("module name is required")
+	}
+
+	if s.Path == "" {
+		return errors.New("module path is required")
+	}
+
+	if s.Template != "" && (len(s.Adapters) != 0 || len(s.Services) != 0) {
+		return errors.New("adapters and services can't be used together with a template")
+	}
+
+	for service, flavor := range s.Services {
+		if flavor == "" {
+			return fmt.Errorf("no flavor specified for service %s", service)
+		}
+	}
+
+	return nil
+}
+
+func isJSON(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
@@ -1,6 +1,8 @@
 package repos
 
 import (
+	"github.com/spf13/afero"
+
 	"github.com/mahcks/gowizard/pkg/domain"
 )
 
@@ -24,6 +26,6 @@ func NewGoCleanArchTemplateRepo() domain.TemplateI {
 	}
 }
 
-func (r *GoCleanArchTemplateRepo) Setup(path string) error {
+func (r *GoCleanArchTemplateRepo) Setup(fs afero.Fs, path string) error {
 	return nil
 }
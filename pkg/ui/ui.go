@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
+	"github.com/mahcks/gowizard/pkg/domain"
 	"github.com/mahcks/gowizard/pkg/generator"
 	"github.com/mahcks/gowizard/pkg/utils"
 )
@@ -205,6 +206,73 @@ func (ui *UI) PromptForServiceFlavor(service string) (string, error) {
 	return flavor, nil
 }
 
+// PromptForLogger prompts for the logger of the project, none generates it without one
+func (ui *UI) PromptForLogger() (string, error) {
+	var options []string
+	descriptions := map[string]string{"none": "Print with fmt instead"}
+	for key, value := range ui.gen.GetLoggers() {
+		options = append(options, key)
+		descriptions[key] = value.GetDisplayName()
+	}
+
+	// Sort the options slice in alphabetical order
+	sort.Strings(options)
+	options = append(options, "none")
+
+	logger := ""
+	prompt := &survey.Select{
+		Message: "Select a logger:",
+		Options: options,
+		Default: "none",
+		Description: func(value string, index int) string {
+			return descriptions[value]
+		},
+	}
+	err := survey.AskOne(prompt, &logger, ui.iconStyles)
+	if err != nil {
+		return "", err
+	}
+
+	return logger, nil
+}
+
+// PromptForMigrations asks which adapter to scaffold migrations for, it's only asked when one of the adapters supports them
+// The answer is empty when there are none
+func (ui *UI) PromptForMigrations(adapters []string) (string, error) {
+	var options []string
+	for _, adapter := range adapters {
+		if _, ok := ui.gen.GetAdapters()[adapter].(domain.MigrationsI); ok {
+			options = append(options, adapter)
+		}
+	}
+
+	if len(options) == 0 {
+		return "", nil
+	}
+
+	// Sort the options slice in alphabetical order
+	sort.Strings(options)
+	options = append(options, "none")
+
+	migrations := ""
+	prompt := &survey.Select{
+		Message: "Scaffold database migrations for:",
+		Options: options,
+		Default: "none",
+		Help:    "Generates a migrations folder with an initial migration and pkg/migrate, which applies them on startup when migrate.on_startup is set",
+	}
+	err := survey.AskOne(prompt, &migrations, ui.iconStyles)
+	if err != nil {
+		return "", err
+	}
+
+	if migrations == "none" {
+		return "", nil
+	}
+
+	return migrations, nil
+}
+
 // PromptForTemplate prompts the user for the template to use that's in the repos template directory
 func (ui *UI) PromptForTemplate() (string, error) {
 	var options []string
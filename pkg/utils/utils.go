@@ -1,15 +1,18 @@
 package utils
 
 import (
+	"bytes"
 	"fmt"
 	"math/rand"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
 	. "github.com/dave/jennifer/jen"
 	"github.com/mgutz/ansi"
+	"github.com/spf13/afero"
 )
 
 // Jptr is a shortcut for jennifer.Op("*")
@@ -20,6 +23,22 @@ func PrintError(msg string, args ...any) {
 	fmt.Println(ansi.Color("[✗] Error:", "red"), ansi.Color(fmt.Sprintf(msg, args...), "red"), ansi.ColorCode("reset"))
 }
 
+// SaveFile renders a jennifer file and writes it to the filesystem, missing folders are created
+func SaveFile(fs afero.Fs, f *File, filename string) error {
+	buf := &bytes.Buffer{}
+	err := f.Render(buf)
+	if err != nil {
+		return err
+	}
+
+	err = fs.MkdirAll(filepath.Dir(filename), 0755)
+	if err != nil {
+		return err
+	}
+
+	return afero.WriteFile(fs, filename, buf.Bytes(), 0644)
+}
+
 // IsDirEmpty checks if a directory is empty or not
 func IsDirEmpty(path string) (bool, error) {
 	dir, err := os.Open(path)
@@ -0,0 +1,509 @@
+package verify
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+// Error is a compile error in a generated file
+type Error struct {
+	File   string // Slash separated path relative to the project
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Msg)
+}
+
+// checker type-checks the packages of a single module
+type checker struct {
+	fs       afero.Fs
+	root     string
+	module   string
+	requires map[string]string // versions of the required modules, keyed by module path
+	modCache string            // GOMODCACHE, third-party packages that aren't vendored are read from it
+	fset     *token.FileSet
+	std      types.Importer
+	packages map[string]*types.Package // packages that were already checked, keyed by import path
+	checking map[string]bool           // packages that are being checked, used to detect import cycles
+	missing  map[string]bool           // third-party packages that aren't vendored or in the module cache
+	errors   []Error
+}
+
+// shared keeps the standard library and the packages of the module cache between checks, they don't change while gowizard runs
+var shared struct {
+	sync.Mutex
+	fset     *token.FileSet
+	std      types.Importer
+	modCache map[string]*types.Package // keyed by directory
+}
+
+// Check type-checks every package of the Go module at root without network access
+// The standard library is type-checked from GOROOT and packages of the module itself from source.
+// Third-party packages are type-checked from vendor or the module cache, at the version go.mod requires or the newest one that was downloaded.
+// Those that aren't available offline are returned as missing, errors that involve them aren't reported.
+func Check(fs afero.Fs, root string) (errs []Error, missing []string, err error) {
+	module, requires, err := readGoMod(fs, root)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shared.Lock()
+	defer shared.Unlock()
+
+	if shared.fset == nil {
+		shared.fset = token.NewFileSet()
+		shared.std = importer.ForCompiler(shared.fset, "source", nil)
+		shared.modCache = map[string]*types.Package{}
+	}
+
+	c := &checker{
+		fs:       fs,
+		root:     root,
+		module:   module,
+		requires: requires,
+		modCache: modCacheDir(),
+		fset:     shared.fset,
+		std:      shared.std,
+		packages: map[string]*types.Package{},
+		checking: map[string]bool{},
+		missing:  map[string]bool{},
+	}
+
+	dirs, err := c.packageDirs()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, dir := range dirs {
+		importPath := module
+		if dir != "." {
+			importPath = path.Join(module, dir)
+		}
+
+		_, err = c.check(importPath)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	sort.SliceStable(c.errors, func(i, j int) bool {
+		if c.errors[i].File != c.errors[j].File {
+			return c.errors[i].File < c.errors[j].File
+		}
+		return c.errors[i].Line < c.errors[j].Line
+	})
+
+	for importPath := range c.missing {
+		missing = append(missing, importPath)
+	}
+	sort.Strings(missing)
+
+	return c.errors, missing, nil
+}
+
+// Import resolves an import of a checked package
+func (c *checker) Import(importPath string) (*types.Package, error) {
+	if importPath == c.module || strings.HasPrefix(importPath, c.module+"/") {
+		return c.check(importPath)
+	}
+
+	// Only the standard library doesn't have a dot in the first path element
+	first, _, _ := strings.Cut(importPath, "/")
+	if !strings.Contains(first, ".") {
+		return c.std.Import(importPath)
+	}
+
+	return c.checkThirdParty(importPath)
+}
+
+// errMissing is returned for imports of third-party packages that aren't available offline, their errors are filtered out
+var errMissing = errors.New("not in vendor or the module cache")
+
+// check type-checks a package of the module, every package is only checked once
+func (c *checker) check(importPath string) (*types.Package, error) {
+	if pkg, ok := c.packages[importPath]; ok {
+		return pkg, nil
+	}
+
+	if c.checking[importPath] {
+		return nil, fmt.Errorf("import cycle through %s", importPath)
+	}
+	c.checking[importPath] = true
+	defer delete(c.checking, importPath)
+
+	dir := filepath.Join(c.root, filepath.FromSlash(strings.TrimPrefix(strings.TrimPrefix(importPath, c.module), "/")))
+	files, err := c.parseDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files in %s", importPath)
+	}
+
+	conf := types.Config{
+		Importer: c,
+		Error: func(err error) {
+			typeErr, ok := err.(types.Error)
+			if !ok {
+				return
+			}
+
+			// Imports of missing third-party packages always fail, uses of them are already ignored by go/types
+			if strings.Contains(typeErr.Msg, "could not import") && strings.Contains(typeErr.Msg, errMissing.Error()) {
+				return
+			}
+
+			c.addError(typeErr.Fset.Position(typeErr.Pos), typeErr.Msg)
+		},
+	}
+
+	// The errors are collected with the Error func, the package is still usable by its importers
+	pkg, _ := conf.Check(importPath, c.fset, files, nil)
+	c.packages[importPath] = pkg
+
+	return pkg, nil
+}
+
+// checkThirdParty type-checks a third-party package from source, errors in the package itself aren't reported
+func (c *checker) checkThirdParty(importPath string) (*types.Package, error) {
+	if pkg, ok := c.packages[importPath]; ok {
+		return pkg, nil
+	}
+
+	if c.missing[importPath] {
+		return nil, errMissing
+	}
+
+	if c.checking[importPath] {
+		return nil, fmt.Errorf("import cycle through %s", importPath)
+	}
+	c.checking[importPath] = true
+	defer delete(c.checking, importPath)
+
+	fs, dir, vendored := c.findThirdParty(importPath)
+	if pkg, ok := shared.modCache[dir]; ok && !vendored {
+		c.packages[importPath] = pkg
+		return pkg, nil
+	}
+
+	var files []*ast.File
+	if dir != "" {
+		ctxt := build.Default
+		ctxt.CgoEnabled = false
+		ctxt.OpenFile = func(path string) (io.ReadCloser, error) {
+			return fs.Open(path)
+		}
+
+		entries, err := afero.ReadDir(fs, dir)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+				continue
+			}
+
+			if match, err := ctxt.MatchFile(dir, entry.Name()); err != nil || !match {
+				continue
+			}
+
+			src, err := afero.ReadFile(fs, filepath.Join(dir, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+
+			file, _ := parser.ParseFile(c.fset, filepath.Join(dir, entry.Name()), src, 0)
+			if file != nil {
+				files = append(files, file)
+			}
+		}
+	}
+
+	if len(files) == 0 {
+		c.missing[importPath] = true
+		return nil, errMissing
+	}
+
+	conf := types.Config{
+		Importer:    c,
+		FakeImportC: true,
+		Error:       func(err error) {},
+	}
+
+	pkg, _ := conf.Check(importPath, c.fset, files, nil)
+	c.packages[importPath] = pkg
+	if !vendored {
+		shared.modCache[dir] = pkg
+	}
+
+	return pkg, nil
+}
+
+// findThirdParty returns the filesystem and directory of a third-party package, the directory is empty when it isn't available
+// Vendored packages are preferred, otherwise the module it belongs to is looked up in the module cache
+func (c *checker) findThirdParty(importPath string) (afero.Fs, string, bool) {
+	vendored := filepath.Join(c.root, "vendor", filepath.FromSlash(importPath))
+	if ok, _ := afero.DirExists(c.fs, vendored); ok {
+		return c.fs, vendored, true
+	}
+
+	if c.modCache == "" {
+		return nil, "", false
+	}
+
+	osFs := afero.NewOsFs()
+
+	// The module is the longest prefix of the import path that's in the module cache
+	for mod := importPath; mod != "." && mod != "/"; mod = path.Dir(mod) {
+		version, ok := c.requires[mod]
+		if !ok {
+			version = newestVersion(osFs, c.modCache, mod)
+		}
+		if version == "" {
+			continue
+		}
+
+		dir := filepath.Join(c.modCache, filepath.FromSlash(escapePath(mod)+"@"+version), filepath.FromSlash(strings.TrimPrefix(importPath[len(mod):], "/")))
+		if ok, _ := afero.DirExists(osFs, dir); ok {
+			return osFs, dir, false
+		}
+	}
+
+	return nil, "", false
+}
+
+// parseDir parses every non-test Go file in a directory, syntax errors are reported as compile errors
+func (c *checker) parseDir(dir string) ([]*ast.File, error) {
+	entries, err := afero.ReadDir(c.fs, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*ast.File
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+
+		filename := filepath.Join(dir, entry.Name())
+		src, err := afero.ReadFile(c.fs, filename)
+		if err != nil {
+			return nil, err
+		}
+
+		file, err := parser.ParseFile(c.fset, filename, src, parser.AllErrors)
+		if err != nil {
+			var list scanner.ErrorList
+			if !errors.As(err, &list) {
+				return nil, err
+			}
+
+			for _, e := range list {
+				c.addError(e.Pos, e.Msg)
+			}
+		}
+
+		if file != nil {
+			files = append(files, file)
+		}
+	}
+
+	return files, nil
+}
+
+// addError records a compile error with its file relative to the module root
+func (c *checker) addError(pos token.Position, msg string) {
+	file, err := filepath.Rel(c.root, pos.Filename)
+	if err != nil {
+		file = pos.Filename
+	}
+
+	c.errors = append(c.errors, Error{
+		File:   filepath.ToSlash(file),
+		Line:   pos.Line,
+		Column: pos.Column,
+		Msg:    msg,
+	})
+}
+
+// packageDirs returns every directory below the root that contains Go files, relative to the root
+func (c *checker) packageDirs() ([]string, error) {
+	found := map[string]bool{}
+
+	err := afero.Walk(c.fs, c.root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			name := info.Name()
+			if p != c.root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if strings.HasSuffix(p, ".go") && !strings.HasSuffix(p, "_test.go") {
+			rel, err := filepath.Rel(c.root, filepath.Dir(p))
+			if err != nil {
+				return err
+			}
+			found[filepath.ToSlash(rel)] = true
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := make([]string, 0, len(found))
+	for dir := range found {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	return dirs, nil
+}
+
+// readGoMod reads the module path and the versions of the required modules from the go.mod file at root
+func readGoMod(fs afero.Fs, root string) (string, map[string]string, error) {
+	b, err := afero.ReadFile(fs, filepath.Join(root, "go.mod"))
+	if err != nil {
+		return "", nil, err
+	}
+
+	module := ""
+	requires := map[string]string{}
+	inRequire := false
+
+	lines := bufio.NewScanner(bytes.NewReader(b))
+	for lines.Scan() {
+		line, _, _ := strings.Cut(lines.Text(), "//")
+		line = strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(line, "module "):
+			module = strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module ")), `"`)
+		case line == "require (":
+			inRequire = true
+		case inRequire && line == ")":
+			inRequire = false
+		case inRequire || strings.HasPrefix(line, "require "):
+			fields := strings.Fields(strings.TrimPrefix(line, "require "))
+			if len(fields) == 2 {
+				requires[strings.Trim(fields[0], `"`)] = fields[1]
+			}
+		}
+	}
+
+	if module == "" {
+		return "", nil, errors.New("no module path found in go.mod")
+	}
+
+	return module, requires, nil
+}
+
+// modCacheDir returns GOMODCACHE, which defaults to pkg/mod in the first GOPATH entry
+func modCacheDir() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+
+	gopath := filepath.SplitList(build.Default.GOPATH)
+	if len(gopath) == 0 {
+		return ""
+	}
+
+	return filepath.Join(gopath[0], "pkg", "mod")
+}
+
+// newestVersion returns the newest version of a module that's in the module cache, or an empty string when there is none
+func newestVersion(fs afero.Fs, modCache, mod string) string {
+	escaped := escapePath(mod)
+	entries, err := afero.ReadDir(fs, filepath.Join(modCache, filepath.FromSlash(path.Dir(escaped))))
+	if err != nil {
+		return ""
+	}
+
+	newest := ""
+	for _, entry := range entries {
+		name, version, ok := strings.Cut(entry.Name(), "@")
+		if !ok || !entry.IsDir() || name != path.Base(escaped) {
+			continue
+		}
+
+		if newest == "" || semverLess(newest, version) {
+			newest = version
+		}
+	}
+
+	return newest
+}
+
+// semverLess reports whether version a is older than version b, i.e. v1.2.0 and v1.10.0
+// Pre-releases are older than the release, they're compared as strings
+func semverLess(a, b string) bool {
+	a, aPre, _ := strings.Cut(strings.TrimSuffix(strings.TrimPrefix(a, "v"), "+incompatible"), "-")
+	b, bPre, _ := strings.Cut(strings.TrimSuffix(strings.TrimPrefix(b, "v"), "+incompatible"), "-")
+
+	aParts, bParts := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		if aParts[i] != bParts[i] {
+			aNum, aErr := strconv.Atoi(aParts[i])
+			bNum, bErr := strconv.Atoi(bParts[i])
+			if aErr != nil || bErr != nil {
+				return aParts[i] < bParts[i]
+			}
+			return aNum < bNum
+		}
+	}
+
+	if len(aParts) != len(bParts) {
+		return len(aParts) < len(bParts)
+	}
+
+	if aPre == "" || bPre == "" {
+		return aPre != "" && bPre == ""
+	}
+
+	return aPre < bPre
+}
+
+// escapePath escapes a module path the way the module cache does, upper case letters become ! and the lower case letter
+func escapePath(mod string) string {
+	var b strings.Builder
+	for _, r := range mod {
+		if 'A' <= r && r <= 'Z' {
+			b.WriteByte('!')
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,88 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestCheckThirdParty(t *testing.T) {
+	modCache := t.TempDir()
+	t.Setenv("GOMODCACHE", modCache)
+
+	// lib is required at v1.0.0, Upper isn't required so the newest version is used
+	cached := map[string]string{
+		"example.com/lib@v1.0.0/lib.go":       "package lib\n\nfunc Hello() string { return \"hello\" }\n",
+		"example.com/lib@v1.1.0/lib.go":       "package lib\n\nfunc Hello() int { return 1 }\n",
+		"example.com/!upper@v1.2.0/upper.go":  "package upper\n\nfunc Old() {}\n",
+		"example.com/!upper@v1.10.0/upper.go": "package upper\n\nfunc New() {}\n",
+	}
+	for file, content := range cached {
+		filename := filepath.Join(modCache, filepath.FromSlash(file))
+
+		err := os.MkdirAll(filepath.Dir(filename), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = os.WriteFile(filename, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fs := afero.NewMemMapFs()
+	project := map[string]string{
+		"go.mod":                           "module example.com/project\n\ngo 1.20\n\nrequire example.com/lib v1.0.0 // indirect\n",
+		"vendor/example.com/vendored/v.go": "package vendored\n\nconst Name = \"vendored\"\n",
+		"main.go": `package main
+
+import (
+	"example.com/lib"
+	"example.com/missing"
+	"example.com/Upper"
+	"example.com/vendored"
+)
+
+func main() {
+	var n int = lib.Hello()
+	var s string = vendored.Name + missing.Name
+	upper.New()
+	upper.Old()
+	_, _ = n, s
+}
+`,
+	}
+	for file, content := range project {
+		err := afero.WriteFile(fs, "/project/"+file, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	errs, missing, err := Check(fs, "/project")
+	if err != nil {
+		t.Fatalf("error checking project: %s", err)
+	}
+
+	if !reflect.DeepEqual(missing, []string{"example.com/missing"}) {
+		t.Errorf("expected example.com/missing to be missing, got %q", missing)
+	}
+
+	var msgs []string
+	for _, e := range errs {
+		msgs = append(msgs, e.Error())
+	}
+
+	expected := []string{
+		"main.go:11:14: cannot use lib.Hello() (value of type string) as int value in variable declaration",
+		"main.go:14:8: undefined: upper.Old",
+	}
+	if strings.Join(msgs, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected the errors:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(msgs, "\n"))
+	}
+}